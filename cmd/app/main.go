@@ -5,40 +5,99 @@ import (
 	"log"
 	"os"
 	"tournament/internal/handler"
+	"tournament/internal/repository/memory"
 	"tournament/internal/repository/pgsql"
+	"tournament/internal/repository/sqlite"
 	"tournament/internal/usecase"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
+	sqlitemigrate "github.com/golang-migrate/migrate/v4/database/sqlite"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/lib/pq"
 )
 
 func main() {
-	db := InitDB()
-	defer db.Close()
-
-	if err := InitMigrations(db); err != nil {
-		log.Fatalf("Ошибка миграции: %v", err)
-	}
+	tournamentRepository, gameRepository, unitOfWork, closeStorage := InitStorage()
+	defer closeStorage()
 
 	router := gin.Default()
+	router.Use(handler.Language())
 
-	tournamentRepository := pgsql.NewTournamentRepository(db)
-	gameRepository := pgsql.NewGameRepository(db)
-	tournamentUsecase := usecase.NewTournamentUsecase(tournamentRepository, gameRepository)
+	tournamentUsecase := usecase.NewTournamentUsecase(tournamentRepository, gameRepository, unitOfWork, InitMatchSimulator())
 
 	tournamentHandler := handler.NewTournamentHandler(tournamentUsecase)
 
+	router.GET("/tournaments", tournamentHandler.ListTournaments)
 	router.POST("/tournaments", tournamentHandler.CreateTournament)
-	router.POST("/tournaments/:id", tournamentHandler.DeleteTournament)
+	router.GET("/tournaments/:id", tournamentHandler.GetTournament)
+	router.PATCH("/tournaments/:id", tournamentHandler.UpdateTournament)
+	router.DELETE("/tournaments/:id", tournamentHandler.DeleteTournament)
+	router.GET("/tournaments/:id/teams", tournamentHandler.ListTeams)
 	router.POST("/tournaments/:id/teams", tournamentHandler.AddTeam)
-	router.GET("/tournaments/:id/run", tournamentHandler.RunTournament)
+	router.POST("/tournaments/:id/teams/import", tournamentHandler.ImportTeams)
+	router.PATCH("/tournaments/:id/teams/:team_id", tournamentHandler.UpdateTeam)
+	router.DELETE("/tournaments/:id/teams/:team_id", tournamentHandler.DeleteTeam)
+	router.GET("/tournaments/:id/games", tournamentHandler.ListGames)
+	router.POST("/tournaments/:id/run", tournamentHandler.RunTournament)
+	router.POST("/tournaments/:id/start", tournamentHandler.StartTournament)
+	router.POST("/tournaments/:id/advance", tournamentHandler.AdvanceTournament)
+	router.POST("/tournaments/:id/cancel", tournamentHandler.CancelTournament)
+	router.POST("/tournaments/:id/resume", tournamentHandler.ResumeTournament)
+	router.POST("/tournaments/:id/games/:game_id/result", tournamentHandler.ReportGameResult)
+	router.GET("/tournaments/:id/result", tournamentHandler.GetTournamentResult)
+	router.GET("/tournaments/:id/bracket", tournamentHandler.GetBracket)
+	router.GET("/tournaments/:id/standings", tournamentHandler.GetStandings)
+
+	// старые маршруты: GET /run запускали прокси и браузеры, предзагружающие ссылки.
+	// Удалить в следующем релизе.
+	router.POST("/tournaments/:id", handler.Deprecated("DELETE /tournaments/:id"), tournamentHandler.DeleteTournament)
+	router.GET("/tournaments/:id/run", handler.Deprecated("POST /tournaments/:id/run"), tournamentHandler.RunTournament)
 
 	router.Run()
 }
 
+// InitStorage выбирает хранилище по переменной STORAGE: postgres (по умолчанию), sqlite или memory.
+// SQLite хранит данные в одном файле (SQLITE_PATH), хранилище в памяти теряет их при остановке сервиса.
+func InitStorage() (usecase.TournamentRepository, usecase.GameRepository, usecase.UnitOfWork, func()) {
+	switch os.Getenv("STORAGE") {
+	case "", "postgres":
+		db := InitDB()
+		if err := InitMigrations(db); err != nil {
+			log.Fatalf("Ошибка миграции: %v", err)
+		}
+		return pgsql.NewTournamentRepository(db), pgsql.NewGameRepository(db), pgsql.NewUnitOfWork(db), func() { db.Close() }
+	case "sqlite":
+		db := InitSQLite()
+		if err := InitSQLiteMigrations(db); err != nil {
+			log.Fatalf("Ошибка миграции: %v", err)
+		}
+		return sqlite.NewTournamentRepository(db), sqlite.NewGameRepository(db), sqlite.NewUnitOfWork(db), func() { db.Close() }
+	case "memory":
+		store := memory.NewStore()
+		log.Println("Данные хранятся в памяти и будут потеряны при остановке")
+		return memory.NewTournamentRepository(store), memory.NewGameRepository(store), memory.NewUnitOfWork(store), func() {}
+	default:
+		log.Fatalf("Неизвестное хранилище: %s", os.Getenv("STORAGE"))
+	}
+	return nil, nil, nil, nil
+}
+
+// InitMatchSimulator выбирает симулятор матчей по переменной MATCH_SIMULATOR: uniform (по умолчанию) или elo.
+func InitMatchSimulator() usecase.MatchSimulator {
+	switch os.Getenv("MATCH_SIMULATOR") {
+	case "", "uniform":
+		return usecase.NewUniformSimulator()
+	case "elo":
+		return usecase.NewEloSimulator()
+	default:
+		log.Fatalf("Неизвестный симулятор матчей: %s", os.Getenv("MATCH_SIMULATOR"))
+	}
+	return nil
+}
+
 func InitDB() *sql.DB {
 	dbUser := os.Getenv("POSTGRES_USER")
 	dbPassword := os.Getenv("POSTGRES_PASSWORD")
@@ -56,15 +115,46 @@ func InitDB() *sql.DB {
 	return db
 }
 
+func InitSQLite() *sql.DB {
+	path := os.Getenv("SQLITE_PATH")
+	if path == "" {
+		path = "tournament.db"
+	}
+
+	dsn, err := sqlite.DSN(path)
+	if err != nil {
+		log.Fatalf("Неверный SQLITE_PATH %q: %v", path, err)
+	}
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		log.Fatalf("Ошибка открытия базы данных SQLite: %v", err)
+	}
+	// SQLite допускает только одну пишущую транзакцию
+	db.SetMaxOpenConns(1)
+
+	return db
+}
+
 func InitMigrations(db *sql.DB) error {
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
 		return err
 	}
+	return applyMigrations(driver, "postgres", "file://db/migrations/pgsql")
+}
+
+func InitSQLiteMigrations(db *sql.DB) error {
+	driver, err := sqlitemigrate.WithInstance(db, &sqlitemigrate.Config{})
+	if err != nil {
+		return err
+	}
+	return applyMigrations(driver, "sqlite", "file://db/migrations/sqlite")
+}
 
+func applyMigrations(driver database.Driver, databaseName string, sourceURL string) error {
 	m, err := migrate.NewWithDatabaseInstance(
-		"file://db/migrations/pgsql",
-		"postgres",
+		sourceURL,
+		databaseName,
 		driver,
 	)
 	if err != nil {
@@ -0,0 +1 @@
+ALTER TABLE tournaments ADD COLUMN format VARCHAR(64) NOT NULL DEFAULT 'classic';
@@ -0,0 +1,4 @@
+DELETE FROM games WHERE team2_id IS NULL;
+ALTER TABLE games ALTER COLUMN team2_id SET NOT NULL;
+ALTER TABLE games DROP COLUMN IF EXISTS position;
+ALTER TABLE games DROP COLUMN IF EXISTS round;
@@ -0,0 +1,4 @@
+ALTER TABLE games ADD COLUMN round INT NOT NULL DEFAULT 0;
+ALTER TABLE games ADD COLUMN position INT NOT NULL DEFAULT 0;
+-- у матча с пропуском (bye) нет второй команды
+ALTER TABLE games ALTER COLUMN team2_id DROP NOT NULL;
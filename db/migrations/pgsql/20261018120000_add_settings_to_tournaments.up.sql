@@ -0,0 +1 @@
+ALTER TABLE tournaments ADD COLUMN settings JSONB NOT NULL DEFAULT '{}';
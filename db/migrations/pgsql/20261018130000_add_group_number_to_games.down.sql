@@ -0,0 +1 @@
+ALTER TABLE games DROP COLUMN IF EXISTS group_number;
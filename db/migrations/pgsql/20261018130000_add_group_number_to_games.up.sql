@@ -0,0 +1 @@
+ALTER TABLE games ADD COLUMN group_number INT NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+CREATE TABLE lots (
+    id SERIAL PRIMARY KEY,
+    tournament_id INT NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    value INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (tournament_id, team_id)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE games DROP COLUMN IF EXISTS maps;
+ALTER TABLE games DROP COLUMN IF EXISTS team2_score;
+ALTER TABLE games DROP COLUMN IF EXISTS team1_score;
+ALTER TABLE games DROP COLUMN IF EXISTS status;
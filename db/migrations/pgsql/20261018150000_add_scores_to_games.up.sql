@@ -0,0 +1,5 @@
+ALTER TABLE games ADD COLUMN status INT NOT NULL DEFAULT 1;
+ALTER TABLE games ADD COLUMN team1_score INT;
+ALTER TABLE games ADD COLUMN team2_score INT;
+ALTER TABLE games ADD COLUMN maps JSONB NOT NULL DEFAULT '[]';
+UPDATE games SET status = 2 WHERE winner_id IS NOT NULL;
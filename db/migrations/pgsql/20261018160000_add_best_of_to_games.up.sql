@@ -0,0 +1 @@
+ALTER TABLE games ADD COLUMN best_of INT NOT NULL DEFAULT 1;
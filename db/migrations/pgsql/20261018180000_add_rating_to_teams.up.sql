@@ -0,0 +1 @@
+ALTER TABLE teams ADD COLUMN rating INT NOT NULL DEFAULT 1500;
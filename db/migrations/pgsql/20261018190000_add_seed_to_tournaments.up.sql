@@ -0,0 +1 @@
+ALTER TABLE tournaments ADD COLUMN seed BIGINT;
@@ -0,0 +1 @@
+ALTER TABLE tournaments DROP COLUMN IF EXISTS status;
@@ -0,0 +1,11 @@
+ALTER TABLE tournaments ADD COLUMN status VARCHAR(32) NOT NULL DEFAULT 'registration';
+
+-- турниры с матчами проводились целиком за один запуск, но итоговые места сохранены не у всех.
+-- Турниры с местами завершены, остальные остаются на последней стадии: POST /tournaments/:id/resume
+-- досчитывает по сыгранным матчам их места и завершает их. Групповой этап - типы 1, 2, 10 и 11.
+UPDATE tournaments SET status = 'finished' WHERE id IN (SELECT tournament_id FROM placements);
+UPDATE tournaments SET status = CASE
+        WHEN id IN (SELECT tournament_id FROM games WHERE game_type NOT IN (1, 2, 10, 11)) THEN 'playoffs'
+        ELSE 'group_stage'
+    END
+WHERE status = 'registration' AND id IN (SELECT tournament_id FROM games);
@@ -0,0 +1 @@
+ALTER TABLE games ADD COLUMN stage INT NOT NULL DEFAULT 0;
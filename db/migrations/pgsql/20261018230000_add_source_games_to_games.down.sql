@@ -0,0 +1,2 @@
+ALTER TABLE games DROP COLUMN IF EXISTS team2_source_game_id;
+ALTER TABLE games DROP COLUMN IF EXISTS team1_source_game_id;
@@ -0,0 +1,3 @@
+-- матчи, из которых пришли участники: победитель или проигравший source-матча, NULL - команда попала по посеву
+ALTER TABLE games ADD COLUMN team1_source_game_id INT REFERENCES games(id) ON DELETE SET NULL;
+ALTER TABLE games ADD COLUMN team2_source_game_id INT REFERENCES games(id) ON DELETE SET NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS teams_tournament_id_lower_name_key;
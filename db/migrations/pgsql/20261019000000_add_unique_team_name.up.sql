@@ -0,0 +1,26 @@
+-- уже зарегистрированные дубли переименовываются: все команды, кроме первой, получают суффикс " #<id>",
+-- а если и такое название занято - " #<id>-2", " #<id>-3" и так далее. Название обрезается так,
+-- чтобы с суффиксом уложиться в 64 символа. Суффиксы разных команд различаются, поэтому новые
+-- названия проверяются только на совпадение с уже существующими.
+UPDATE teams t SET name = (
+    SELECT c.name
+    FROM (
+        SELECT s.n, LEFT(t.name, 64 - LENGTH(s.suffix)) || s.suffix AS name
+        FROM (
+            SELECT a.n, ' #' || t.id || CASE WHEN a.n > 1 THEN '-' || a.n ELSE '' END AS suffix
+            FROM generate_series(1, (SELECT COUNT(*) FROM teams m WHERE m.tournament_id = t.tournament_id) + 1) AS a(n)
+        ) s
+    ) c
+    WHERE NOT EXISTS (
+        SELECT 1 FROM teams o
+        WHERE o.tournament_id = t.tournament_id AND o.id <> t.id AND LOWER(o.name) = LOWER(c.name)
+    )
+    ORDER BY c.n
+    LIMIT 1
+)
+WHERE EXISTS (
+    SELECT 1 FROM teams o
+    WHERE o.tournament_id = t.tournament_id AND LOWER(o.name) = LOWER(t.name) AND o.id < t.id
+);
+-- название команды уникально в турнире без учета регистра: "Navi" и "NAVI" - одна команда
+CREATE UNIQUE INDEX teams_tournament_id_lower_name_key ON teams (tournament_id, LOWER(name));
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS games_tournament_id_slot_key;
@@ -0,0 +1,2 @@
+-- на каждом месте сетки один матч: параллельные шаги турнира не могут создать стадию дважды
+CREATE UNIQUE INDEX games_tournament_id_slot_key ON games (tournament_id, game_type, group_number, round, position);
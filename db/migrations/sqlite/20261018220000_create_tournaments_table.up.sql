@@ -0,0 +1,8 @@
+CREATE TABLE tournaments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    format VARCHAR(64) NOT NULL DEFAULT 'classic',
+    status VARCHAR(32) NOT NULL DEFAULT 'registration',
+    settings TEXT NOT NULL DEFAULT '{}',
+    seed BIGINT
+);
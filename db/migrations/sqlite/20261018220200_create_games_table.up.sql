@@ -0,0 +1,18 @@
+CREATE TABLE games (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tournament_id INT NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    team1_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    -- у матча с пропуском (bye) нет второй команды
+    team2_id INT REFERENCES teams(id) ON DELETE CASCADE,
+    game_type INT NOT NULL,
+    stage INT NOT NULL DEFAULT 0,
+    group_number INT NOT NULL DEFAULT 0,
+    round INT NOT NULL DEFAULT 0,
+    position INT NOT NULL DEFAULT 0,
+    best_of INT NOT NULL DEFAULT 1,
+    status INT NOT NULL DEFAULT 1,
+    team1_score INT,
+    team2_score INT,
+    maps TEXT NOT NULL DEFAULT '[]',
+    winner_id INT REFERENCES teams(id) ON DELETE SET NULL
+);
@@ -0,0 +1,9 @@
+CREATE TABLE placements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tournament_id INT NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    place_from INT NOT NULL,
+    place_to INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tournament_id, team_id)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE games DROP COLUMN team2_source_game_id;
+ALTER TABLE games DROP COLUMN team1_source_game_id;
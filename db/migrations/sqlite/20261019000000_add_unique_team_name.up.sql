@@ -0,0 +1,30 @@
+-- уже зарегистрированные дубли переименовываются: все команды, кроме первой, получают суффикс " #<id>",
+-- а если и такое название занято - " #<id>-2", " #<id>-3" и так далее. Название обрезается так,
+-- чтобы с суффиксом уложиться в 64 символа. Суффиксы разных команд различаются, поэтому новые
+-- названия проверяются только на совпадение с уже существующими.
+UPDATE teams SET name = (
+    WITH RECURSIVE attempts(n) AS (
+        SELECT 1
+        UNION ALL
+        SELECT n + 1 FROM attempts
+        WHERE n <= (SELECT COUNT(*) FROM teams c WHERE c.tournament_id = teams.tournament_id)
+    ),
+    candidates(n, name) AS (
+        SELECT n, substr(teams.name, 1, 64 - length(suffix)) || suffix
+        FROM (SELECT n, ' #' || teams.id || CASE WHEN n > 1 THEN '-' || n ELSE '' END AS suffix FROM attempts)
+    )
+    SELECT candidates.name FROM candidates
+    WHERE NOT EXISTS (
+        SELECT 1 FROM teams o
+        WHERE o.tournament_id = teams.tournament_id AND o.id <> teams.id AND o.name = candidates.name COLLATE NOCASE
+    )
+    ORDER BY n
+    LIMIT 1
+)
+WHERE EXISTS (
+    SELECT 1 FROM teams o
+    WHERE o.tournament_id = teams.tournament_id AND o.name = teams.name COLLATE NOCASE AND o.id < teams.id
+);
+-- название команды уникально в турнире без учета регистра; NOCASE сравнивает без учета регистра
+-- только латиницу, остальные названия проверяются при регистрации команды
+CREATE UNIQUE INDEX teams_tournament_id_name_key ON teams (tournament_id, name COLLATE NOCASE);
@@ -0,0 +1,2 @@
+ALTER TABLE teams DROP COLUMN region;
+ALTER TABLE teams DROP COLUMN seed;
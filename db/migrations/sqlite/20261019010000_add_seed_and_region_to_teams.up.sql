@@ -0,0 +1,3 @@
+-- seed - место в посеве, 0 - по порядку регистрации после посеянных команд
+ALTER TABLE teams ADD COLUMN seed INT NOT NULL DEFAULT 0;
+ALTER TABLE teams ADD COLUMN region VARCHAR(64) NOT NULL DEFAULT '';
@@ -1,16 +1,118 @@
 package entity
 
+// дивизионы A и B - групповой этап турниров, созданных до появления GAME_TYPE_GROUP
 const GAME_TYPE_DIVISION_A = 1
 const GAME_TYPE_DIVISION_B = 2
 const GAME_TYPE_PLAYOFF_STAGE_1 = 3
 const GAME_TYPE_PLAYOFF_SEMIFINAL = 4
 const GAME_TYPE_PLAYOFF_FINAL = 5
+const GAME_TYPE_WINNERS_BRACKET = 6
+const GAME_TYPE_LOSERS_BRACKET = 7
+const GAME_TYPE_GRAND_FINAL = 8
+const GAME_TYPE_GRAND_FINAL_RESET = 9
+const GAME_TYPE_SWISS = 10
+const GAME_TYPE_GROUP = 11
+const GAME_TYPE_PLAYOFF_THIRD_PLACE = 12
+
+// названия стадий по типам матчей, используются в настройках турнира
+var GameTypeNames = map[int]string{
+	GAME_TYPE_DIVISION_A:          "division_a",
+	GAME_TYPE_DIVISION_B:          "division_b",
+	GAME_TYPE_PLAYOFF_STAGE_1:     "playoff",
+	GAME_TYPE_PLAYOFF_SEMIFINAL:   "semifinal",
+	GAME_TYPE_PLAYOFF_FINAL:       "final",
+	GAME_TYPE_WINNERS_BRACKET:     "winners_bracket",
+	GAME_TYPE_LOSERS_BRACKET:      "losers_bracket",
+	GAME_TYPE_GRAND_FINAL:         "grand_final",
+	GAME_TYPE_GRAND_FINAL_RESET:   "grand_final_reset",
+	GAME_TYPE_SWISS:               "swiss",
+	GAME_TYPE_GROUP:               "group",
+	GAME_TYPE_PLAYOFF_THIRD_PLACE: "third_place",
+}
+
+const GAME_STATUS_SCHEDULED = 1
+const GAME_STATUS_FINISHED = 2
+const GAME_STATUS_IN_PROGRESS = 3
+
+var GameStatusNames = map[int]string{
+	GAME_STATUS_SCHEDULED:   "scheduled",
+	GAME_STATUS_FINISHED:    "finished",
+	GAME_STATUS_IN_PROGRESS: "in_progress",
+}
 
 type Game struct {
 	ID           int
 	TournamentID int
 	Team1ID      int
-	Team2ID      int
+	Team2ID      int // 0, если у команды нет соперника (bye)
 	GameType     int
+	Stage        int // номер стадии турнира, на которой создан матч, с 1 по порядку генерации
+	Group        int // номер группы с 1, 0 - матч не группового этапа
+	Round        int
+	Position     int
+	BestOf       int // матч - серия до BestOf/2+1 побед на картах
+	Status       int
+	Team1Score   *int
+	Team2Score   *int
+	Maps         []GameMap // счет по картам/сетам, если матч из них состоит
 	WinnerId     *int
+	// матчи, победитель или проигравший которых стал первой и второй командой; 0 - команда пришла по посеву
+	Team1SourceGameID int
+	Team2SourceGameID int
+}
+
+type GameMap struct {
+	Team1Score int `json:"team1_score"`
+	Team2Score int `json:"team2_score"`
+}
+
+func (g Game) WinsNeeded() int {
+	return g.BestOf/2 + 1
+}
+
+// IsGroupStage - матч группового этапа (группы, швейцарская система, дивизионы), а не плей-офф.
+func (g Game) IsGroupStage() bool {
+	switch g.GameType {
+	case GAME_TYPE_GROUP, GAME_TYPE_SWISS, GAME_TYPE_DIVISION_A, GAME_TYPE_DIVISION_B:
+		return true
+	}
+	return false
+}
+
+func (g Game) IsBye() bool {
+	return g.Team2ID == 0
+}
+
+func (g Game) IsPlayed() bool {
+	return g.Status == GAME_STATUS_FINISHED
+}
+
+func (g Game) IsDraw() bool {
+	return g.IsPlayed() && !g.IsBye() && g.WinnerId == nil
+}
+
+// AllowsDraw - ничья возможна только в одиночных матчах, где не нужно определять, кто проходит дальше.
+func (g Game) AllowsDraw() bool {
+	if g.BestOf > 1 {
+		return false
+	}
+	switch g.GameType {
+	case GAME_TYPE_DIVISION_A, GAME_TYPE_DIVISION_B, GAME_TYPE_GROUP, GAME_TYPE_SWISS:
+		return true
+	}
+	return false
+}
+
+// Scores - счет матча для первой и второй команды. Для матчей без записанного счета победа считается как 1:0.
+func (g Game) Scores() (int, int) {
+	if g.Team1Score != nil && g.Team2Score != nil {
+		return *g.Team1Score, *g.Team2Score
+	}
+	if g.WinnerId == nil {
+		return 0, 0
+	}
+	if *g.WinnerId == g.Team1ID {
+		return 1, 0
+	}
+	return 0, 1
 }
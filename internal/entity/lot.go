@@ -0,0 +1,8 @@
+package entity
+
+// Lot - жребий команды, вытянутый при формировании групп. Последний критерий при равенстве в таблице.
+type Lot struct {
+	TournamentID int
+	TeamID       int
+	Value        int
+}
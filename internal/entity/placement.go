@@ -0,0 +1,10 @@
+package entity
+
+// Placement - итоговое место команды в турнире. Команды, выбывшие на одной стадии,
+// делят места: например, проигравшие в четвертьфинале занимают места с 5 по 8.
+type Placement struct {
+	TournamentID int
+	TeamID       int
+	PlaceFrom    int
+	PlaceTo      int
+}
@@ -1,7 +1,16 @@
 package entity
 
+// DEFAULT_RATING - рейтинг команды, для которой он не указан при регистрации
+const DEFAULT_RATING = 1500
+
 type Team struct {
 	ID           int
 	TournamentID int
 	Name         string
+	// Rating - сила команды по шкале Эло, используется при симуляции матчей
+	Rating int
+	// Seed - место в посеве, 0 - команда сеется после посеянных в порядке регистрации
+	Seed int
+	// Region - регион, из которого заявлена команда
+	Region string
 }
@@ -1,6 +1,50 @@
 package entity
 
+// этапы жизненного цикла турнира
+const (
+	TOURNAMENT_STATUS_REGISTRATION = "registration"
+	TOURNAMENT_STATUS_SEEDING      = "seeding"
+	TOURNAMENT_STATUS_GROUP_STAGE  = "group_stage"
+	TOURNAMENT_STATUS_PLAYOFFS     = "playoffs"
+	TOURNAMENT_STATUS_FINISHED     = "finished"
+	TOURNAMENT_STATUS_CANCELLED    = "cancelled"
+)
+
 type Tournament struct {
-	ID   int
-	Name string
+	ID       int
+	Name     string
+	Format   string
+	Status   string
+	Settings TournamentSettings
+	// Seed - сид генератора случайных чисел, с которым проведен турнир; nil - турнир еще не запускался
+	Seed *int64
+}
+
+// TournamentSettings - параметры формата, хранятся вместе с турниром.
+type TournamentSettings struct {
+	// GrandFinalReset - если команда из нижней сетки выигрывает гранд-финал, играется второй гранд-финал
+	GrandFinalReset bool `json:"grand_final_reset"`
+	// SwissRounds - количество раундов швейцарской системы, 0 - по количеству команд
+	SwissRounds int `json:"swiss_rounds"`
+	// Groups - количество групп, 0 - по GroupSize, а если не задан и он - две группы
+	Groups int `json:"groups"`
+	// GroupSize - максимальное количество команд в группе, 0 - без ограничения
+	GroupSize int `json:"group_size"`
+	// AdvancePerGroup - сколько команд из каждой группы выходят в плей-офф, 0 - четыре
+	AdvancePerGroup int `json:"advance_per_group"`
+	// Crossover - пары первого раунда плей-офф по местам в группах, например ["A1-B4", "B2-A3"]
+	Crossover []string `json:"crossover"`
+	// Tiebreakers - порядок критериев при равенстве очков в группе
+	Tiebreakers []string `json:"tiebreakers"`
+	// BestOf - формат серий по стадиям (названия из GameTypeNames): 1, 3, 5 или 7 карт, по умолчанию 1
+	BestOf map[string]int `json:"best_of"`
+	// ThirdPlaceMatch - матч за третье место между проигравшими в полуфиналах
+	ThirdPlaceMatch bool `json:"third_place_match"`
+}
+
+func (s TournamentSettings) BestOfFor(gameType int) int {
+	if bestOf, ok := s.BestOf[GameTypeNames[gameType]]; ok {
+		return bestOf
+	}
+	return 1
 }
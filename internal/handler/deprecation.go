@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated отмечает маршрут, оставленный для совместимости на один релиз: ответ не меняется,
+// но клиент получает заголовок Deprecation и подсказку, каким маршрутом его заменить.
+func Deprecated(replacement string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		c.Header("Warning", fmt.Sprintf(`299 - "Deprecated API: use %s instead"`, replacement))
+		c.Next()
+	}
+}
@@ -3,34 +3,128 @@ package handler
 import (
 	"errors"
 	"fmt"
+	"log"
+	"net/http"
+	"tournament/internal/usecase"
 
+	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
+const PROBLEM_CONTENT_TYPE = "application/problem+json"
+
+// коды ошибок, которые возникают до usecase: неверный запрос и внутренняя ошибка
+const (
+	ERROR_CODE_MALFORMED_REQUEST = "malformed_request"
+	ERROR_CODE_INVALID_PARAMETER = "invalid_parameter"
+	ERROR_CODE_VALIDATION_FAILED = "validation_failed"
+	ERROR_CODE_UNSUPPORTED_MEDIA = "unsupported_media_type"
+	ERROR_CODE_INTERNAL          = "internal_error"
+)
+
+// ErrorResponse - ошибка в формате RFC 7807 (application/problem+json).
+// Code - стабильный код ошибки, Errors - ошибки отдельных полей запроса.
 type ErrorResponse struct {
-	Errors     map[string]string `json:"message"`
-	StatusCode int               `json:"status_code"`
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     string            `json:"code"`
+	Errors   map[string]string `json:"errors,omitempty"`
 }
 
-func Converter(err error) map[string]string {
-	var validationErrors validator.ValidationErrors
+// errorStatuses - HTTP-статусы для видов ошибок usecase
+var errorStatuses = []struct {
+	Kind   error
+	Status int
+}{
+	{usecase.ErrNotFound, http.StatusNotFound},
+	{usecase.ErrConflict, http.StatusConflict},
+	{usecase.ErrInvalidState, http.StatusConflict},
+	{usecase.ErrValidation, http.StatusUnprocessableEntity},
+}
 
-	var result = make(map[string]string)
+func problem(c *gin.Context, status int, code string, detail string, fields map[string]string) {
+	c.Header("Content-Type", PROBLEM_CONTENT_TYPE)
+	c.AbortWithStatusJSON(status, ErrorResponse{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+		Code:     code,
+		Errors:   fields,
+	})
+}
 
-	if errors.As(err, &validationErrors) {
-		for _, fe := range validationErrors {
-			switch fe.Tag() {
-			case "required":
-				result[fe.Field()] = fmt.Sprintf("Поле %s обязательно", fe.Field())
-			case "email":
-				result[fe.Field()] = fmt.Sprintf("Поле %s должно быть валидным email", fe.Field())
-			default:
-				result[fe.Field()] = fmt.Sprintf("Ошибка в поле %s", fe.Field())
+// respondError отвечает на ошибку usecase. Ошибки, не относящиеся к предметной области
+// (база недоступна и т.п.), клиенту не раскрываются и только пишутся в лог.
+func respondError(c *gin.Context, err error) {
+	var domainErr *usecase.Error
+	if errors.As(err, &domainErr) {
+		lang := requestLanguage(c)
+		for _, mapping := range errorStatuses {
+			if errors.Is(domainErr, mapping.Kind) {
+				problem(c, mapping.Status, domainErr.Code, translate(lang, domainErr.Format, domainErr.Args...), fieldMessages(lang, domainErr.Fields))
+				return
 			}
 		}
-		return result
 	}
 
-	result["body"] = "пустое содержимое"
+	log.Printf("Ошибка при обработке %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	problem(c, http.StatusInternalServerError, ERROR_CODE_INTERNAL, translate(requestLanguage(c), "internal server error"), nil)
+}
+
+// MalformedError - ошибка формата тела запроса, найденная самим handler (например, в CSV нет заголовка).
+// Format - английский текст сообщения, как у usecase.Error, переводится по каталогу messages.
+type MalformedError struct {
+	Format string
+	Args   []any
+}
+
+func (e *MalformedError) Error() string {
+	return fmt.Sprintf(e.Format, e.Args...)
+}
+
+func malformed(format string, args ...any) error {
+	return &MalformedError{Format: format, Args: args}
+}
+
+// respondBindError отвечает на ошибку разбора запроса: 422 с ошибками полей
+// или 400, если тело запроса не удалось прочитать.
+func respondBindError(c *gin.Context, err error) {
+	lang := requestLanguage(c)
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		problem(c, http.StatusUnprocessableEntity, ERROR_CODE_VALIDATION_FAILED, translate(lang, "request validation failed"), Converter(lang, validationErrors))
+		return
+	}
+	var malformedErr *MalformedError
+	if errors.As(err, &malformedErr) {
+		problem(c, http.StatusBadRequest, ERROR_CODE_MALFORMED_REQUEST, translate(lang, "malformed request: %s", translate(lang, malformedErr.Format, malformedErr.Args...)), nil)
+		return
+	}
+	problem(c, http.StatusBadRequest, ERROR_CODE_MALFORMED_REQUEST, translate(lang, "malformed request: %s", err), nil)
+}
+
+// fieldMessages переводит ошибки полей из usecase.Error на язык lang.
+func fieldMessages(lang string, fields map[string]error) map[string]string {
+	if len(fields) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(fields))
+	for field, err := range fields {
+		var domainErr *usecase.Error
+		if errors.As(err, &domainErr) {
+			result[field] = translate(lang, domainErr.Format, domainErr.Args...)
+		} else {
+			result[field] = err.Error()
+		}
+	}
 	return result
 }
+
+func respondInvalidParameter(c *gin.Context, name string) {
+	problem(c, http.StatusBadRequest, ERROR_CODE_INVALID_PARAMETER, translate(requestLanguage(c), "Invalid %s", name), nil)
+}
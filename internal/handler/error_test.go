@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestProblemResponses(t *testing.T) {
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+		field  string
+	}{
+		{name: "invalid id", method: http.MethodGet, path: "/tournaments/abc", status: http.StatusBadRequest, code: ERROR_CODE_INVALID_PARAMETER},
+		{name: "malformed body", method: http.MethodPost, path: "/tournaments", body: `{"name":`, status: http.StatusBadRequest, code: ERROR_CODE_MALFORMED_REQUEST},
+		{name: "missing field", method: http.MethodPost, path: "/tournaments", body: `{"format":"swiss"}`, status: http.StatusUnprocessableEntity, code: ERROR_CODE_VALIDATION_FAILED, field: "name"},
+		{name: "not found", method: http.MethodGet, path: "/tournaments/42", status: http.StatusNotFound, code: "tournament_not_found"},
+		{name: "invalid state", method: http.MethodGet, path: "/tournaments/1/result", status: http.StatusConflict, code: "tournament_not_finished"},
+		{name: "domain validation", method: http.MethodPost, path: "/tournaments", body: `{"name":"Cup","format":"round_robin_deluxe"}`, status: http.StatusUnprocessableEntity, code: "unknown_format"},
+		{name: "invalid sort", method: http.MethodGet, path: "/tournaments/1/teams?sort=password", status: http.StatusUnprocessableEntity, code: "invalid_sort"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newRouter()
+			createTournament(t, router, 2)
+
+			rec := request(t, router, tc.method, tc.path, tc.body)
+			if rec.Code != tc.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tc.status, rec.Body)
+			}
+			if contentType := rec.Header().Get("Content-Type"); contentType != PROBLEM_CONTENT_TYPE {
+				t.Errorf("Content-Type %q, want %q", contentType, PROBLEM_CONTENT_TYPE)
+			}
+
+			var res ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+				t.Fatalf("decode %s: %v", rec.Body, err)
+			}
+			if res.Status != tc.status || res.Code != tc.code || res.Title != http.StatusText(tc.status) || res.Type != "about:blank" {
+				t.Errorf("problem %+v, want status %d and code %s", res, tc.status, tc.code)
+			}
+			if path, _, _ := strings.Cut(tc.path, "?"); res.Instance != path {
+				t.Errorf("instance %q, want %q", res.Instance, path)
+			}
+			if tc.field != "" && res.Errors[tc.field] == "" {
+				t.Errorf("errors %v, want an error for %s", res.Errors, tc.field)
+			}
+		})
+	}
+}
+
+func TestInternalErrorIsNotExposed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/tournaments/1", nil)
+
+	respondError(c, errors.New("pq: password authentication failed"))
+
+	var res ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body, err)
+	}
+	if rec.Code != http.StatusInternalServerError || res.Code != ERROR_CODE_INTERNAL {
+		t.Errorf("status %d with code %s, want 500 with %s", rec.Code, res.Code, ERROR_CODE_INTERNAL)
+	}
+	if strings.Contains(rec.Body.String(), "password") {
+		t.Errorf("internal error exposed: %s", rec.Body)
+	}
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"tournament/internal/repository/memory"
+	"tournament/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newRouter - маршруты сервиса, как в cmd/app, над хранилищем в памяти.
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	store := memory.NewStore()
+	tournamentHandler := NewTournamentHandler(usecase.NewTournamentUsecase(
+		memory.NewTournamentRepository(store),
+		memory.NewGameRepository(store),
+		memory.NewUnitOfWork(store),
+		usecase.NewEloSimulator(),
+	))
+
+	router := gin.New()
+	router.Use(Language())
+	router.GET("/tournaments", tournamentHandler.ListTournaments)
+	router.POST("/tournaments", tournamentHandler.CreateTournament)
+	router.GET("/tournaments/:id", tournamentHandler.GetTournament)
+	router.PATCH("/tournaments/:id", tournamentHandler.UpdateTournament)
+	router.DELETE("/tournaments/:id", tournamentHandler.DeleteTournament)
+	router.GET("/tournaments/:id/teams", tournamentHandler.ListTeams)
+	router.POST("/tournaments/:id/teams", tournamentHandler.AddTeam)
+	router.POST("/tournaments/:id/teams/import", tournamentHandler.ImportTeams)
+	router.PATCH("/tournaments/:id/teams/:team_id", tournamentHandler.UpdateTeam)
+	router.DELETE("/tournaments/:id/teams/:team_id", tournamentHandler.DeleteTeam)
+	router.GET("/tournaments/:id/games", tournamentHandler.ListGames)
+	router.POST("/tournaments/:id/run", tournamentHandler.RunTournament)
+	router.POST("/tournaments/:id/start", tournamentHandler.StartTournament)
+	router.POST("/tournaments/:id/advance", tournamentHandler.AdvanceTournament)
+	router.POST("/tournaments/:id/cancel", tournamentHandler.CancelTournament)
+	router.POST("/tournaments/:id/resume", tournamentHandler.ResumeTournament)
+	router.POST("/tournaments/:id/games/:game_id/result", tournamentHandler.ReportGameResult)
+	router.GET("/tournaments/:id/result", tournamentHandler.GetTournamentResult)
+	router.GET("/tournaments/:id/bracket", tournamentHandler.GetBracket)
+	router.GET("/tournaments/:id/standings", tournamentHandler.GetStandings)
+
+	router.POST("/tournaments/:id", Deprecated("DELETE /tournaments/:id"), tournamentHandler.DeleteTournament)
+	router.GET("/tournaments/:id/run", Deprecated("POST /tournaments/:id/run"), tournamentHandler.RunTournament)
+	return router
+}
+
+// request выполняет запрос к router и возвращает ответ.
+func request(t *testing.T, router http.Handler, method string, path string, body string, header ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// createTournament создает турнир на выбывание с teams командами.
+func createTournament(t *testing.T, router http.Handler, teams int) {
+	t.Helper()
+	if rec := request(t, router, http.MethodPost, "/tournaments", `{"name":"Cup","format":"single_elimination"}`); rec.Code != http.StatusOK {
+		t.Fatalf("create tournament: %d %s", rec.Code, rec.Body)
+	}
+	for i := 0; i < teams; i++ {
+		body := `{"name":"Team ` + string(rune('A'+i)) + `"}`
+		if rec := request(t, router, http.MethodPost, "/tournaments/1/teams", body); rec.Code != http.StatusOK {
+			t.Fatalf("add team: %d %s", rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestDeprecatedRoutes(t *testing.T) {
+	cases := []struct {
+		name        string
+		method      string
+		path        string
+		deprecated  bool
+		replacement string
+	}{
+		{name: "run", method: http.MethodPost, path: "/tournaments/1/run"},
+		{name: "run by GET", method: http.MethodGet, path: "/tournaments/1/run", deprecated: true, replacement: "POST /tournaments/:id/run"},
+		{name: "delete", method: http.MethodDelete, path: "/tournaments/1"},
+		{name: "delete by POST", method: http.MethodPost, path: "/tournaments/1", deprecated: true, replacement: "DELETE /tournaments/:id"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newRouter()
+			createTournament(t, router, 4)
+
+			// старый маршрут отвечает так же, как новый, но с заголовками устаревания
+			rec := request(t, router, tc.method, tc.path, "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body)
+			}
+			if got := rec.Header().Get("Deprecation"); (got == "true") != tc.deprecated {
+				t.Errorf("Deprecation header %q", got)
+			}
+			if warning := rec.Header().Get("Warning"); tc.deprecated && !strings.Contains(warning, tc.replacement) {
+				t.Errorf("Warning header %q does not mention %s", warning, tc.replacement)
+			}
+		})
+	}
+}
+
+func TestUpdateAndDeleteRoutes(t *testing.T) {
+	router := newRouter()
+	createTournament(t, router, 3)
+
+	rec := request(t, router, http.MethodPatch, "/tournaments/1", `{"name":"Spring Cup"}`)
+	var tournament struct {
+		Tournament struct{ Name string } `json:"tournament"`
+	}
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &tournament) != nil || tournament.Tournament.Name != "Spring Cup" {
+		t.Errorf("rename tournament: %d %s", rec.Code, rec.Body)
+	}
+
+	rec = request(t, router, http.MethodPatch, "/tournaments/1/teams/2", `{"rating":1800}`)
+	var team struct {
+		Team struct{ Rating int } `json:"team"`
+	}
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &team) != nil || team.Team.Rating != 1800 {
+		t.Errorf("update team rating: %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := request(t, router, http.MethodDelete, "/tournaments/1/teams/3", ""); rec.Code != http.StatusOK {
+		t.Errorf("delete team: %d %s", rec.Code, rec.Body)
+	}
+	if rec := request(t, router, http.MethodDelete, "/tournaments/1/teams/x", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("delete team with invalid id: %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := request(t, router, http.MethodDelete, "/tournaments/1", ""); rec.Code != http.StatusOK {
+		t.Errorf("delete tournament: %d %s", rec.Code, rec.Body)
+	}
+	if rec := request(t, router, http.MethodGet, "/tournaments/1", ""); rec.Code == http.StatusOK {
+		t.Errorf("deleted tournament: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestRunTournamentSeed(t *testing.T) {
+	cases := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+		code   string
+		seed   int64
+	}{
+		{name: "seed in query", path: "/tournaments/1/run?seed=42", status: http.StatusOK, seed: 42},
+		{name: "seed in body", path: "/tournaments/1/run", body: `{"seed":42}`, status: http.StatusOK, seed: 42},
+		{name: "same seed in both", path: "/tournaments/1/run?seed=42", body: `{"seed":42}`, status: http.StatusOK, seed: 42},
+		{name: "empty body", path: "/tournaments/1/run?seed=42", body: `{}`, status: http.StatusOK, seed: 42},
+		{name: "conflicting seeds", path: "/tournaments/1/run?seed=7", body: `{"seed":42}`, status: http.StatusBadRequest, code: ERROR_CODE_INVALID_PARAMETER},
+		{name: "malformed body", path: "/tournaments/1/run", body: `{"seed":"forty-two"}`, status: http.StatusBadRequest, code: ERROR_CODE_MALFORMED_REQUEST},
+		{name: "invalid query", path: "/tournaments/1/run?seed=x", status: http.StatusBadRequest, code: ERROR_CODE_INVALID_PARAMETER},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newRouter()
+			createTournament(t, router, 4)
+
+			rec := request(t, router, http.MethodPost, tc.path, tc.body)
+			if rec.Code != tc.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tc.status, rec.Body)
+			}
+			if tc.status != http.StatusOK {
+				var res ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil || res.Code != tc.code {
+					t.Errorf("problem %s, want code %s", rec.Body, tc.code)
+				}
+				return
+			}
+			var res usecase.TournamentResultResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil || res.Seed != tc.seed {
+				t.Errorf("result %s, want seed %d", rec.Body, tc.seed)
+			}
+		})
+	}
+}
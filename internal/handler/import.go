@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"tournament/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const MIME_CSV = "text/csv"
+
+// IMPORT_FILE_FIELD - поле формы multipart/form-data с файлом заявок
+const IMPORT_FILE_FIELD = "file"
+
+// utf8BOM - метка порядка байтов, с которой таблицы сохраняют CSV в UTF-8
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// importTeamsRequest читает команды из JSON-массива или CSV в теле запроса либо из файла формы
+// (поле file, .json или .csv) и проверяет каждую строку. Ошибки строк не возвращаются сразу,
+// а передаются в usecase, чтобы клиент получил их одним ответом вместе с остальными.
+func importTeamsRequest(c *gin.Context) (usecase.ImportTeamsRequest, bool) {
+	var req usecase.ImportTeamsRequest
+	lang := requestLanguage(c)
+
+	var err error
+	switch c.ContentType() {
+	case binding.MIMEJSON:
+		req, err = decodeTeamsJSON(c.Request.Body)
+	case MIME_CSV:
+		req, err = decodeTeamsCSV(lang, c.Request.Body)
+	case binding.MIMEMultipartPOSTForm:
+		req, err = decodeTeamsFile(lang, c)
+	default:
+		problem(c, http.StatusUnsupportedMediaType, ERROR_CODE_UNSUPPORTED_MEDIA, translate(lang, "unsupported content type %q, expected application/json, text/csv or multipart/form-data", c.ContentType()), nil)
+		return req, false
+	}
+	if err != nil {
+		respondBindError(c, err)
+		return req, false
+	}
+
+	for i, row := range req.Teams {
+		var validationErrors validator.ValidationErrors
+		if err := binding.Validator.ValidateStruct(&row); errors.As(err, &validationErrors) {
+			for field, message := range Converter(lang, validationErrors) {
+				addImportError(&req, i, field, message)
+			}
+		}
+	}
+	return req, true
+}
+
+// addImportError записывает уже переведенную ошибку поля field команды с индексом index.
+func addImportError(req *usecase.ImportTeamsRequest, index int, field string, message string) {
+	if req.Invalid == nil {
+		req.Invalid = map[int]map[string]error{}
+	}
+	if req.Invalid[index] == nil {
+		req.Invalid[index] = map[string]error{}
+	}
+	// ошибка разбора (например, seed не число) точнее ошибки валидации того же поля
+	if _, ok := req.Invalid[index][field]; !ok {
+		req.Invalid[index][field] = errors.New(message)
+	}
+}
+
+func decodeTeamsJSON(r io.Reader) (usecase.ImportTeamsRequest, error) {
+	var req usecase.ImportTeamsRequest
+	if err := json.NewDecoder(r).Decode(&req.Teams); err != nil {
+		return req, err
+	}
+	return req, nil
+}
+
+func decodeTeamsFile(lang string, c *gin.Context) (usecase.ImportTeamsRequest, error) {
+	var req usecase.ImportTeamsRequest
+	header, err := c.FormFile(IMPORT_FILE_FIELD)
+	if err != nil {
+		return req, err
+	}
+	file, err := header.Open()
+	if err != nil {
+		return req, err
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(header.Filename), ".json") {
+		return decodeTeamsJSON(file)
+	}
+	return decodeTeamsCSV(lang, file)
+}
+
+// decodeTeamsCSV читает CSV с заголовком: обязательная колонка name и необязательные seed, rating, region,
+// остальные колонки пропускаются. Разделитель - запятая или точка с запятой, как сохраняют таблицы.
+// Для каждой команды запоминается строка файла, нечисловые seed и rating записываются как ошибки строк.
+func decodeTeamsCSV(lang string, r io.Reader) (usecase.ImportTeamsRequest, error) {
+	var req usecase.ImportTeamsRequest
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return req, err
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	firstLine, _, _ := strings.Cut(string(data), "\n")
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return req, malformed("csv file is empty")
+	}
+	if err != nil {
+		return req, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return req, malformed("csv header has no name column")
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return req, err
+		}
+		value := func(column string) string {
+			if i, ok := columns[column]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		// пустые строки в конце таблицы сохраняются как строки из одних разделителей
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		row := usecase.AddTeamRequest{
+			Name:   value("name"),
+			Region: value("region"),
+		}
+		numbers := []struct {
+			Column string
+			Value  *int
+		}{
+			{"seed", &row.Seed},
+			{"rating", &row.Rating},
+		}
+		for _, number := range numbers {
+			text := value(number.Column)
+			if text == "" {
+				continue
+			}
+			n, err := strconv.Atoi(text)
+			if err != nil {
+				addImportError(&req, len(req.Teams), number.Column, fmt.Sprintf(validationMessages[lang]["numeric"], number.Column))
+				continue
+			}
+			*number.Value = n
+		}
+		line, _ := reader.FieldPos(0)
+		req.Teams = append(req.Teams, row)
+		req.Lines = append(req.Lines, line)
+	}
+	return req, nil
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"testing"
+	"tournament/internal/usecase"
+)
+
+// importTeams отправляет заявки на импорт и возвращает ответ.
+func importTeams(t *testing.T, contentType string, body string) (int, usecase.ImportTeamsResponse, ErrorResponse) {
+	t.Helper()
+	router := newRouter()
+	createTournament(t, router, 0)
+	rec := request(t, router, http.MethodPost, "/tournaments/1/teams/import", body, "Content-Type", contentType)
+
+	var res usecase.ImportTeamsResponse
+	var problem ErrorResponse
+	target := any(&res)
+	if rec.Code != http.StatusOK {
+		target = &problem
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), target); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body, err)
+	}
+	return rec.Code, res, problem
+}
+
+// multipartFile - тело multipart/form-data с файлом заявок и его Content-Type.
+func multipartFile(t *testing.T, filename string, content string) (string, string) {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(IMPORT_FILE_FIELD, filename)
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	part.Write([]byte(content))
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close form: %v", err)
+	}
+	return writer.FormDataContentType(), body.String()
+}
+
+func TestImportTeams(t *testing.T) {
+	csvFile, csvBody := multipartFile(t, "teams.CSV", "name,seed\nAlpha,1\nBravo,\n")
+	jsonFile, jsonBody := multipartFile(t, "teams.json", `[{"name":"Alpha","seed":1},{"name":"Bravo"}]`)
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{name: "json", contentType: "application/json", body: `[{"name":"Alpha","seed":1},{"name":"Bravo"}]`},
+		{name: "csv", contentType: "text/csv", body: "name,seed,rating\nAlpha,1,\nBravo,,\n"},
+		// таблицы сохраняют CSV с BOM, точкой с запятой и пустыми строками в конце
+		{name: "csv from a spreadsheet", contentType: "text/csv; charset=utf-8", body: "\uFEFFRegion; Name ;Seed;Comment\nEU;Alpha;1;first\n;Bravo;;\n;;;\n"},
+		{name: "csv file", contentType: csvFile, body: csvBody},
+		{name: "json file", contentType: jsonFile, body: jsonBody},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, res, problem := importTeams(t, tc.contentType, tc.body)
+			if status != http.StatusOK {
+				t.Fatalf("status %d: %+v", status, problem)
+			}
+			if len(res.Teams) != 2 || res.Teams[0].Name != "Alpha" || res.Teams[0].Seed != 1 || res.Teams[1].Name != "Bravo" || res.Teams[1].Seed != 0 {
+				t.Errorf("imported teams %+v", res.Teams)
+			}
+		})
+	}
+}
+
+func TestImportTeamsErrors(t *testing.T) {
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		code        string
+		fields      []string
+	}{
+		{name: "unsupported media", contentType: "text/plain", body: "Alpha", status: http.StatusUnsupportedMediaType, code: ERROR_CODE_UNSUPPORTED_MEDIA},
+		{name: "malformed json", contentType: "application/json", body: `[{"name":`, status: http.StatusBadRequest, code: ERROR_CODE_MALFORMED_REQUEST},
+		{name: "empty csv", contentType: "text/csv", body: "\n", status: http.StatusBadRequest, code: ERROR_CODE_MALFORMED_REQUEST},
+		{name: "csv without name column", contentType: "text/csv", body: "team,seed\nAlpha,1\n", status: http.StatusBadRequest, code: ERROR_CODE_MALFORMED_REQUEST},
+		{name: "row validation", contentType: "application/json", body: `[{"name":"Alpha"},{"seed":-1}]`, status: http.StatusUnprocessableEntity, code: usecase.ERROR_CODE_INVALID_IMPORT, fields: []string{"rows[1].name", "rows[1].seed"}},
+		{name: "not a number in csv", contentType: "text/csv", body: "name,seed,rating\nAlpha,first,\nBravo,,high\n", status: http.StatusUnprocessableEntity, code: usecase.ERROR_CODE_INVALID_IMPORT, fields: []string{"lines[2].seed", "lines[3].rating"}},
+		// пустые строки пропускаются, но ошибки указывают на строки файла
+		{name: "csv with blank lines", contentType: "text/csv", body: "name,seed\n\nAlpha,first\n,\nBravo,second\n", status: http.StatusUnprocessableEntity, code: usecase.ERROR_CODE_INVALID_IMPORT, fields: []string{"lines[3].seed", "lines[5].seed"}},
+		{name: "duplicate names", contentType: "application/json", body: `[{"name":"Alpha"},{"name":"alpha"}]`, status: http.StatusUnprocessableEntity, code: usecase.ERROR_CODE_INVALID_IMPORT, fields: []string{"rows[1].name"}},
+		// ошибки разбора и ошибки usecase возвращаются одним ответом
+		{name: "row validation and duplicate names", contentType: "application/json", body: `[{"name":"Alpha"},{"name":"alpha"},{"seed":-1}]`, status: http.StatusUnprocessableEntity, code: usecase.ERROR_CODE_INVALID_IMPORT, fields: []string{"rows[1].name", "rows[2].name", "rows[2].seed"}},
+		{name: "not a number and duplicate names in csv", contentType: "text/csv", body: "name,seed\nAlpha,\nalpha,\nBravo,first\n", status: http.StatusUnprocessableEntity, code: usecase.ERROR_CODE_INVALID_IMPORT, fields: []string{"lines[3].name", "lines[4].seed"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, _, problem := importTeams(t, tc.contentType, tc.body)
+			if status != tc.status || problem.Code != tc.code {
+				t.Fatalf("status %d, code %s, want %d and %s: %+v", status, problem.Code, tc.status, tc.code, problem)
+			}
+			for _, field := range tc.fields {
+				if problem.Errors[field] == "" {
+					t.Errorf("errors %v, want an error for %s", problem.Errors, field)
+				}
+			}
+		})
+	}
+}
+
+func TestImportCSVErrorsAreLocalised(t *testing.T) {
+	cases := []struct {
+		body   string
+		lang   string
+		detail string
+	}{
+		{body: "\n", lang: LANG_RU, detail: "Некорректный запрос: CSV-файл пуст"},
+		{body: "\n", lang: LANG_EN, detail: "malformed request: csv file is empty"},
+		{body: "team,seed\nAlpha,1\n", lang: LANG_RU, detail: "Некорректный запрос: В заголовке CSV нет колонки name"},
+		{body: "team,seed\nAlpha,1\n", lang: LANG_EN, detail: "malformed request: csv header has no name column"},
+	}
+	for _, tc := range cases {
+		router := newRouter()
+		createTournament(t, router, 0)
+		rec := request(t, router, http.MethodPost, "/tournaments/1/teams/import", tc.body, "Content-Type", MIME_CSV, "Accept-Language", tc.lang)
+		var problem ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("decode %s: %v", rec.Body, err)
+		}
+		if rec.Code != http.StatusBadRequest || problem.Code != ERROR_CODE_MALFORMED_REQUEST || problem.Detail != tc.detail {
+			t.Errorf("%q in %s: status %d, %+v, want detail %q", tc.body, tc.lang, rec.Code, problem, tc.detail)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/language"
+)
+
+const LANG_RU = "ru"
+const LANG_EN = "en"
+
+// первый язык используется, если клиент не прислал Accept-Language или ни один его язык не поддерживается
+var supportedLanguages = []string{LANG_RU, LANG_EN}
+
+var languageMatcher = language.NewMatcher([]language.Tag{language.Russian, language.English})
+
+// validationMessages - сообщения об ошибках полей по тегам валидатора: %[1]s - поле, %[2]s - параметр тега.
+// Для строк и списков min, max и len ограничивают длину, поэтому у них отдельные сообщения с суффиксом .len.
+var validationMessages = map[string]map[string]string{
+	LANG_RU: {
+		"required": "Поле %[1]s обязательно",
+		"email":    "Поле %[1]s должно быть валидным email",
+		"min":      "Поле %[1]s должно быть не меньше %[2]s",
+		"max":      "Поле %[1]s должно быть не больше %[2]s",
+		"len":      "Поле %[1]s должно быть равно %[2]s",
+		"min.len":  "Длина поля %[1]s должна быть не меньше %[2]s",
+		"max.len":  "Длина поля %[1]s должна быть не больше %[2]s",
+		"len.len":  "Длина поля %[1]s должна быть равна %[2]s",
+		"gt":       "Поле %[1]s должно быть больше %[2]s",
+		"gte":      "Поле %[1]s должно быть не меньше %[2]s",
+		"lt":       "Поле %[1]s должно быть меньше %[2]s",
+		"lte":      "Поле %[1]s должно быть не больше %[2]s",
+		"oneof":    "Поле %[1]s должно быть одним из: %[2]s",
+		"numeric":  "Поле %[1]s должно быть числом",
+		"default":  "Ошибка в поле %[1]s",
+	},
+	LANG_EN: {
+		"required": "Field %[1]s is required",
+		"email":    "Field %[1]s must be a valid email",
+		"min":      "Field %[1]s must be at least %[2]s",
+		"max":      "Field %[1]s must be at most %[2]s",
+		"len":      "Field %[1]s must be equal to %[2]s",
+		"min.len":  "Field %[1]s must be at least %[2]s characters long",
+		"max.len":  "Field %[1]s must be at most %[2]s characters long",
+		"len.len":  "Field %[1]s must be exactly %[2]s characters long",
+		"gt":       "Field %[1]s must be greater than %[2]s",
+		"gte":      "Field %[1]s must be at least %[2]s",
+		"lt":       "Field %[1]s must be less than %[2]s",
+		"lte":      "Field %[1]s must be at most %[2]s",
+		"oneof":    "Field %[1]s must be one of: %[2]s",
+		"numeric":  "Field %[1]s must be a number",
+		"default":  "Field %[1]s is invalid",
+	},
+}
+
+// messages - переводы сообщений об ошибках. Ключ - английский формат сообщения из usecase или handler,
+// поэтому для английского каталог не нужен. Сообщение без перевода отдается на английском.
+var messages = map[string]map[string]string{
+	LANG_RU: {
+		// handler
+		"Invalid %s":                "Неверный параметр %s",
+		"malformed request: %s":     "Некорректный запрос: %s",
+		"request validation failed": "Ошибка в полях запроса",
+		"internal server error":     "Внутренняя ошибка сервера",
+		"unsupported content type %q, expected application/json, text/csv or multipart/form-data": "Неподдерживаемый тип содержимого %q, ожидается application/json, text/csv или multipart/form-data",
+		"seed %d in query conflicts with seed %d in body":                                         "Сид %d в query не совпадает с сидом %d в теле запроса",
+
+		// импорт команд из CSV
+		"csv file is empty":             "CSV-файл пуст",
+		"csv header has no name column": "В заголовке CSV нет колонки name",
+
+		// не найдено
+		"tournament %d not found":                    "Турнир %d не найден",
+		"team %d is not registered in tournament %d": "Команда %d не зарегистрирована в турнире %d",
+		"game %d not found in tournament %d":         "Матч %d не найден в турнире %d",
+		"tournament has no stage with standings":     "В турнире нет стадии с турнирной таблицей",
+		"tournament has no %s stage":                 "В турнире нет стадии %s",
+
+		// конфликты и состояние турнира
+		"game result has already been reported":                                     "Результат матча уже внесен",
+		"tournament was already run with seed %d":                                   "Турнир уже запущен с сидом %d",
+		"tournament cannot move from %s to %s":                                      "Турнир не может перейти из %s в %s",
+		"registration is closed, tournament is in %s":                               "Регистрация закрыта, турнир в статусе %s",
+		"rating can only be changed during registration, tournament is in %s":       "Рейтинг можно менять только во время регистрации, турнир в статусе %s",
+		"seed can only be changed during registration, tournament is in %s":         "Место в посеве можно менять только во время регистрации, турнир в статусе %s",
+		"seed %d is already taken by team %q":                                       "Место %d в посеве уже занято командой %q",
+		"format and settings can only be changed before start, tournament is in %s": "Формат и настройки можно менять только до старта, турнир в статусе %s",
+		"tournament has already started, it is in %s":                               "Турнир уже начался, он в статусе %s",
+		"tournament is already finished":                                            "Турнир уже завершен",
+		"tournament is cancelled":                                                   "Турнир отменен",
+		"tournament in %s cannot be resumed":                                        "Турнир в статусе %s нельзя продолжить",
+		"tournament has not been played yet":                                        "Турнир еще не сыгран",
+		"results are not accepted while tournament is in %s":                        "Результаты не принимаются, пока турнир в статусе %s",
+		"bracket is waiting for unfinished games":                                   "Сетка ждет завершения несыгранных матчей",
+		"%d games of the current stage are not finished yet":                        "Не завершено матчей текущей стадии: %d",
+		"bye game has no result to report":                                          "У матча с пропуском (bye) нет результата",
+		"at least 2 teams are required to close registration, got %d":               "Для закрытия регистрации нужно минимум 2 команды, зарегистрировано %d",
+		"at least 2 teams are required for playoff, got %d":                         "Для плей-офф нужно минимум 2 команды, есть %d",
+		"at least 2 teams are required for swiss, got %d":                           "Для швейцарской системы нужно минимум 2 команды, есть %d",
+		"expected at least %d teams, got %d":                                        "Нужно минимум %d команд, есть %d",
+		"expected at most %d teams for %d groups of %d":                             "Нужно не больше %d команд для %d групп по %d",
+		"group %s has no team at place %d":                                          "В группе %s нет команды на %d месте",
+		"tournament has no teams":                                                   "В турнире нет команд",
+		"team %q is already registered in tournament %d":                            "Команда %q уже зарегистрирована в турнире %d",
+		"tournament %s format allows at most %d teams, got %d":                      "Формат турнира %s допускает не больше %d команд, получилось %d",
+
+		// валидация
+		"unknown tournament format %q":                                   "Неизвестный формат турнира %q",
+		"unknown stage %q":                                               "Неизвестная стадия %q",
+		"unknown stage %q in best_of":                                    "Неизвестная стадия %q в best_of",
+		"unknown game status %q":                                         "Неизвестный статус матча %q",
+		"stage %q has no standings":                                      "У стадии %q нет турнирной таблицы",
+		"cannot sort by %q, expected one of: %s":                         "Нельзя сортировать по %q, допустимые поля: %s",
+		"best_of for %s must be 1, 3, 5 or 7":                            "best_of для %s должен быть 1, 3, 5 или 7",
+		"groups must be between 1 and 26":                                "groups должно быть от 1 до 26",
+		"group_size must be at least 2":                                  "group_size должно быть не меньше 2",
+		"advance_per_group must be positive":                             "advance_per_group должно быть положительным",
+		"swiss_rounds must be positive":                                  "swiss_rounds должно быть положительным",
+		"swiss_rounds must be between 1 and %d":                          "swiss_rounds должно быть от 1 до %d",
+		"crossover must contain a power of two pairs":                    "В crossover должно быть число пар, равное степени двойки",
+		"invalid crossover pair %q, expected format like A1-B4":          "Неверная пара crossover %q, ожидается формат вида A1-B4",
+		"invalid crossover place %q":                                     "Неверное место crossover %q",
+		"invalid crossover place %q: place must be between 1 and %d":     "Неверное место crossover %q: место должно быть от 1 до %d",
+		"invalid crossover place %q: there are only %d groups":           "Неверное место crossover %q: групп всего %d",
+		"crossover place %q is used twice":                               "Место crossover %q использовано дважды",
+		"crossover requires groups to be set":                            "Для crossover нужно задать groups",
+		"crossover must cover all %d qualifying places, got %d":          "crossover должен покрывать все %d мест выхода из групп, покрыто %d",
+		"unknown tiebreaker %q":                                          "Неизвестный критерий %q",
+		"tiebreaker %q is used twice":                                    "Критерий %q использован дважды",
+		"team1_score and team2_score are required unless maps are given": "Нужны team1_score и team2_score, если не переданы карты",
+		"scores must not be negative":                                    "Счет не может быть отрицательным",
+		"map scores must not be negative":                                "Счет карты не может быть отрицательным",
+		"score %d:%d does not match maps won %d:%d":                      "Счет %d:%d не совпадает с выигранными картами %d:%d",
+		"invalid score %d:%d for best of %d":                             "Неверный счет %d:%d для серии до %d",
+		"this game cannot end in a draw":                                 "Этот матч не может закончиться вничью",
+		"winner_id does not match the reported score":                    "winner_id не совпадает со счетом",
+		"team name must be between %d and %d characters":                 "Название команды должно быть от %d до %d символов",
+		"no teams to import":                                             "Нет команд для импорта",
+		"%d of %d imported teams are invalid":                            "Ошибки в %d из %d импортируемых команд",
+	},
+}
+
+// requestLanguage выбирает язык ответа по заголовку Accept-Language и сообщает его в Content-Language.
+func requestLanguage(c *gin.Context) string {
+	tags, _, _ := language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	// без совпадения Match возвращает не первый язык, а наиболее распространенный, поэтому он проверяется отдельно
+	_, index, confidence := languageMatcher.Match(tags...)
+	lang := supportedLanguages[0]
+	if confidence != language.No {
+		lang = supportedLanguages[index]
+	}
+	c.Header("Content-Language", lang)
+	return lang
+}
+
+// translate форматирует сообщение на языке lang, format - английский текст сообщения.
+func translate(lang string, format string, args ...any) string {
+	if translated, ok := messages[lang][format]; ok {
+		format = translated
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func init() {
+	// в ошибках поля называются так же, как в запросе клиента, а не как в структуре Go
+	if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		engine.RegisterTagNameFunc(requestFieldName)
+	}
+}
+
+// requestFieldName - имя поля в запросе: из тега json, для параметров query - из тега form.
+func requestFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// Language сообщает язык ответа в Content-Language для любого ответа, а не только для ошибок.
+func Language() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestLanguage(c)
+		c.Next()
+	}
+}
+
+// Converter переводит ошибки валидатора в сообщения по полям на языке lang.
+func Converter(lang string, validationErrors validator.ValidationErrors) map[string]string {
+	catalog := validationMessages[lang]
+	result := make(map[string]string)
+
+	for _, fe := range validationErrors {
+		key := fe.Tag()
+		switch fe.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+			if _, ok := catalog[key+".len"]; ok {
+				key += ".len"
+			}
+		}
+		message, ok := catalog[key]
+		if !ok {
+			message = catalog["default"]
+		}
+		result[fe.Field()] = fmt.Sprintf(message, fe.Field(), fe.Param())
+	}
+	return result
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func TestRequestLanguage(t *testing.T) {
+	cases := map[string]string{
+		"":                     LANG_RU,
+		"ru-RU,ru;q=0.9":       LANG_RU,
+		"en-US,en;q=0.9":       LANG_EN,
+		"en-GB":                LANG_EN,
+		"de-DE":                LANG_RU,
+		"de-DE,en;q=0.5":       LANG_EN,
+		"en;q=0.5,ru;q=0.8":    LANG_RU,
+		"not a language tag!!": LANG_RU,
+	}
+	for header, want := range cases {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set("Accept-Language", header)
+
+		if lang := requestLanguage(c); lang != want {
+			t.Errorf("Accept-Language %q: %s, want %s", header, lang, want)
+		}
+		if got := rec.Header().Get("Content-Language"); got != want {
+			t.Errorf("Accept-Language %q: Content-Language %q, want %s", header, got, want)
+		}
+	}
+}
+
+func TestLocalisedProblem(t *testing.T) {
+	cases := []struct {
+		lang   string
+		detail string
+	}{
+		{lang: "en", detail: "tournament 42 not found"},
+		{lang: "ru", detail: "Турнир 42 не найден"},
+		{lang: "", detail: "Турнир 42 не найден"},
+	}
+	for _, tc := range cases {
+		rec := request(t, newRouter(), http.MethodGet, "/tournaments/42", "", "Accept-Language", tc.lang)
+		var res ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatalf("decode %s: %v", rec.Body, err)
+		}
+		if res.Detail != tc.detail {
+			t.Errorf("Accept-Language %q: detail %q, want %q", tc.lang, res.Detail, tc.detail)
+		}
+		// код ошибки от языка не зависит
+		if res.Code != "tournament_not_found" {
+			t.Errorf("Accept-Language %q: code %q", tc.lang, res.Code)
+		}
+	}
+}
+
+func TestConverter(t *testing.T) {
+	// поля называются как в запросе: по тегу json, а для query - по тегу form
+	type request struct {
+		Name   string `json:"name,omitempty" binding:"required"`
+		Title  string `json:"title" binding:"min=3"`
+		Rating int    `json:"rating" binding:"min=0"`
+		Page   int    `form:"page" binding:"min=1"`
+		Note   string `json:"-" binding:"required"`
+	}
+	err := binding.Validator.ValidateStruct(request{Title: "ab", Rating: -1})
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		t.Fatalf("validation error %v", err)
+	}
+
+	want := map[string]map[string]string{
+		LANG_EN: {
+			"name":   "Field name is required",
+			"title":  "Field title must be at least 3 characters long",
+			"rating": "Field rating must be at least 0",
+			"page":   "Field page must be at least 1",
+			"Note":   "Field Note is required",
+		},
+		LANG_RU: {
+			"name":   "Поле name обязательно",
+			"title":  "Длина поля title должна быть не меньше 3",
+			"rating": "Поле rating должно быть не меньше 0",
+			"page":   "Поле page должно быть не меньше 1",
+			"Note":   "Поле Note обязательно",
+		},
+	}
+	for lang, fields := range want {
+		got := Converter(lang, validationErrors)
+		if len(got) != len(fields) {
+			t.Errorf("%s: %v, want %d fields", lang, got, len(fields))
+		}
+		for field, message := range fields {
+			if got[field] != message {
+				t.Errorf("%s %s: %q, want %q", lang, field, got[field], message)
+			}
+		}
+	}
+}
+
+func TestContentLanguageOnEveryResponse(t *testing.T) {
+	router := newRouter()
+	createTournament(t, router, 2)
+	for _, path := range []string{"/tournaments", "/tournaments/1", "/tournaments/1/teams", "/tournaments/42"} {
+		for header, want := range map[string]string{"en-US": LANG_EN, "": LANG_RU} {
+			rec := request(t, router, http.MethodGet, path, "", "Accept-Language", header)
+			if got := rec.Header().Get("Content-Language"); got != want {
+				t.Errorf("GET %s with Accept-Language %q: status %d, Content-Language %q, want %s", path, header, rec.Code, got, want)
+			}
+		}
+	}
+}
+
+// TestMessagesCatalogIsComplete проверяет, что у каждого сообщения об ошибке из usecase и handler есть русский перевод.
+func TestMessagesCatalogIsComplete(t *testing.T) {
+	// функции, создающие сообщения, и номер аргумента с форматом
+	formatArgs := map[string]int{
+		"NotFound":     1,
+		"Conflict":     1,
+		"InvalidState": 1,
+		"Validation":   1,
+		"notFound":     2,
+		"translate":    1,
+		"malformed":    0,
+	}
+
+	files, err := filepath.Glob("../usecase/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handlerFiles, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, handlerFiles...)
+
+	found := 0
+	fset := token.NewFileSet()
+	for _, file := range files {
+		parsed, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", file, err)
+		}
+		ast.Inspect(parsed, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			var name string
+			switch fun := call.Fun.(type) {
+			case *ast.Ident:
+				name = fun.Name
+			case *ast.SelectorExpr:
+				name = fun.Sel.Name
+			}
+			index, ok := formatArgs[name]
+			if !ok || len(call.Args) <= index {
+				return true
+			}
+			literal, ok := call.Args[index].(*ast.BasicLit)
+			if !ok || literal.Kind != token.STRING {
+				return true
+			}
+			format, err := strconv.Unquote(literal.Value)
+			if err != nil {
+				t.Fatalf("%s: %v", fset.Position(literal.Pos()), err)
+			}
+			found++
+			if _, ok := messages[LANG_RU][format]; !ok {
+				t.Errorf("%s: no ru translation for %q", fset.Position(literal.Pos()), format)
+			}
+			return true
+		})
+	}
+	if found == 0 {
+		t.Fatal("no messages found")
+	}
+}
@@ -1,9 +1,10 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
-	"tournament/internal/entity"
 	"tournament/internal/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -22,17 +23,14 @@ func NewTournamentHandler(tournamentUsecase *usecase.TournamentUseCase) *Tournam
 func (t *TournamentHandler) CreateTournament(c *gin.Context) {
 	var req usecase.CreateTournamentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Errors: Converter(err), StatusCode: http.StatusBadRequest})
+		respondBindError(c, err)
 		return
 	}
 
 	res, err := t.TournamentUsecase.CreateTournament(req)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Errors:     map[string]string{"message:": err.Error()},
-			StatusCode: http.StatusBadRequest,
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -44,100 +42,208 @@ func (t *TournamentHandler) RunTournament(c *gin.Context) {
 
 	tournamentID, err := strconv.Atoi(tournamentIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Errors:     map[string]string{"message:": "Invalid tournament_id"},
-			StatusCode: http.StatusBadRequest,
-		})
+		respondInvalidParameter(c, "tournament_id")
 		return
 	}
 
-	// Делим команды на 2 дивизиона и формируем расписание
-	err = t.TournamentUsecase.GenerateDivisionSchedule(tournamentID)
+	req, ok := runTournamentRequest(c)
+	if !ok {
+		return
+	}
+
+	// формат турнира сам определяет стадии, их расписание и результаты
+	res, err := t.TournamentUsecase.RunTournament(tournamentID, req)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+
+}
+
+func (t *TournamentHandler) StartTournament(c *gin.Context) {
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
+	if err != nil {
+		respondInvalidParameter(c, "tournament_id")
+		return
+	}
+
+	req, ok := runTournamentRequest(c)
+	if !ok {
+		return
+	}
+
+	res, err := t.TournamentUsecase.StartTournament(tournamentID, req)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (t *TournamentHandler) AdvanceTournament(c *gin.Context) {
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
+	if err != nil {
+		respondInvalidParameter(c, "tournament_id")
+		return
+	}
+
+	req, ok := runTournamentRequest(c)
+	if !ok {
+		return
+	}
+
+	res, err := t.TournamentUsecase.AdvanceTournament(tournamentID, req)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (t *TournamentHandler) CancelTournament(c *gin.Context) {
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
+	if err != nil {
+		respondInvalidParameter(c, "tournament_id")
+		return
+	}
+
+	res, err := t.TournamentUsecase.CancelTournament(tournamentID)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (t *TournamentHandler) ResumeTournament(c *gin.Context) {
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Errors:     map[string]string{"message:": err.Error()},
-			StatusCode: http.StatusBadRequest,
-		})
+		respondInvalidParameter(c, "tournament_id")
 		return
 	}
-	// генерация результатов матчей в дивизионах
-	err = t.TournamentUsecase.GenerateDivisionResult(tournamentID)
+
+	res, err := t.TournamentUsecase.ResumeTournament(tournamentID)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Errors:     map[string]string{"message:": err.Error()},
-			StatusCode: http.StatusBadRequest,
-		})
+		respondError(c, err)
 		return
 	}
-	// формирование расписания первой стадии плейофф по результатам матчей в дивизионах
-	err = t.TournamentUsecase.GeneratePlayoffStage1Schedule(tournamentID)
 
+	c.JSON(http.StatusOK, res)
+}
+
+func (t *TournamentHandler) ReportGameResult(c *gin.Context) {
+	var req usecase.ReportGameResultRequest
+
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Errors:     map[string]string{"message:": err.Error()},
-			StatusCode: http.StatusBadRequest,
-		})
+		respondInvalidParameter(c, "tournament_id")
 		return
 	}
-	// генерация результатов первой стадии плейофф
-	err = t.TournamentUsecase.GenerateResultByGameType(tournamentID, entity.GAME_TYPE_PLAYOFF_STAGE_1)
 
+	gameIDStr := c.Param("game_id")
+
+	gameID, err := strconv.Atoi(gameIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Errors:     map[string]string{"message:": err.Error()},
-			StatusCode: http.StatusBadRequest,
-		})
+		respondInvalidParameter(c, "game_id")
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
 		return
 	}
 
-	// формирование расписания полуфинала по результатам первой стадии плейофф
-	err = t.TournamentUsecase.GenerateSemininalSchedule(tournamentID)
+	res, err := t.TournamentUsecase.ReportGameResult(tournamentID, gameID, req)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Errors:     map[string]string{"message:": err.Error()},
-			StatusCode: http.StatusBadRequest,
-		})
+		respondError(c, err)
 		return
 	}
 
-	// генерация результатов полуфинала
-	err = t.TournamentUsecase.GenerateResultByGameType(tournamentID, entity.GAME_TYPE_PLAYOFF_SEMIFINAL)
+	c.JSON(http.StatusOK, res)
+}
 
+func (t *TournamentHandler) GetTournamentResult(c *gin.Context) {
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Errors:     map[string]string{"message:": err.Error()},
-			StatusCode: http.StatusBadRequest,
-		})
+		respondInvalidParameter(c, "tournament_id")
 		return
 	}
 
-	// формирование	расписания финала
-	err = t.TournamentUsecase.GenerateFinalSchedule(tournamentID)
+	res, err := t.TournamentUsecase.GetTournamentResult(tournamentID)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Errors:     map[string]string{"message:": err.Error()},
-			StatusCode: http.StatusBadRequest,
-		})
+		respondError(c, err)
 		return
 	}
 
-	// генерация результата финала
+	c.JSON(http.StatusOK, res)
+}
+
+func (t *TournamentHandler) GetBracket(c *gin.Context) {
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
+	if err != nil {
+		respondInvalidParameter(c, "tournament_id")
+		return
+	}
 
-	res, err := t.TournamentUsecase.GenerateFinalResult(tournamentID)
+	res, err := t.TournamentUsecase.GetBracket(tournamentID)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Errors:     map[string]string{"message:": err.Error()},
-			StatusCode: http.StatusBadRequest,
-		})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, res)
+}
 
+func (t *TournamentHandler) GetStandings(c *gin.Context) {
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
+	if err != nil {
+		respondInvalidParameter(c, "tournament_id")
+		return
+	}
+
+	var req usecase.StandingsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	res, err := t.TournamentUsecase.GetStandings(tournamentID, req)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
 }
 
 func (t *TournamentHandler) AddTeam(c *gin.Context) {
@@ -147,25 +253,130 @@ func (t *TournamentHandler) AddTeam(c *gin.Context) {
 
 	tournamentID, err := strconv.Atoi(tournamentIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Errors:     map[string]string{"message:": "Invalid tournament_id"},
-			StatusCode: http.StatusBadRequest,
-		})
+		respondInvalidParameter(c, "tournament_id")
 		return
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Errors: Converter(err), StatusCode: http.StatusBadRequest})
+		respondBindError(c, err)
 		return
 	}
 
 	res, err := t.TournamentUsecase.AddTeam(tournamentID, req)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Errors:     map[string]string{"message:": err.Error()},
-			StatusCode: http.StatusBadRequest,
-		})
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (t *TournamentHandler) ImportTeams(c *gin.Context) {
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
+	if err != nil {
+		respondInvalidParameter(c, "tournament_id")
+		return
+	}
+
+	req, ok := importTeamsRequest(c)
+	if !ok {
+		return
+	}
+
+	res, err := t.TournamentUsecase.ImportTeams(tournamentID, req)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (t *TournamentHandler) UpdateTournament(c *gin.Context) {
+	var req usecase.UpdateTournamentRequest
+
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
+	if err != nil {
+		respondInvalidParameter(c, "tournament_id")
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	res, err := t.TournamentUsecase.UpdateTournament(tournamentID, req)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (t *TournamentHandler) UpdateTeam(c *gin.Context) {
+	var req usecase.UpdateTeamRequest
+
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
+	if err != nil {
+		respondInvalidParameter(c, "tournament_id")
+		return
+	}
+
+	teamIDStr := c.Param("team_id")
+
+	teamID, err := strconv.Atoi(teamIDStr)
+	if err != nil {
+		respondInvalidParameter(c, "team_id")
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	res, err := t.TournamentUsecase.UpdateTeam(tournamentID, teamID, req)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (t *TournamentHandler) DeleteTeam(c *gin.Context) {
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
+	if err != nil {
+		respondInvalidParameter(c, "tournament_id")
+		return
+	}
+
+	teamIDStr := c.Param("team_id")
+
+	teamID, err := strconv.Atoi(teamIDStr)
+	if err != nil {
+		respondInvalidParameter(c, "team_id")
+		return
+	}
+
+	res, err := t.TournamentUsecase.DeleteTeam(tournamentID, teamID)
+
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -177,20 +388,126 @@ func (t *TournamentHandler) DeleteTournament(c *gin.Context) {
 
 	tournamentID, err := strconv.Atoi(tournamentIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Errors:     map[string]string{"message:": "Invalid tournament_id"},
-			StatusCode: http.StatusBadRequest,
-		})
+		respondInvalidParameter(c, "tournament_id")
 		return
 	}
 
 	res, err := t.TournamentUsecase.DeleteTournament(tournamentID)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Errors:     map[string]string{"message:": err.Error()},
-			StatusCode: http.StatusBadRequest,
-		})
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// runTournamentRequest разбирает необязательный сид из query (?seed=) или JSON-тела ({"seed": ...});
+// если сид передан и там, и там, значения должны совпадать. При ошибке сразу отвечает 400.
+func runTournamentRequest(c *gin.Context) (usecase.RunTournamentRequest, bool) {
+	var req usecase.RunTournamentRequest
+	// тело необязательно: POST без тела запускает турнир с сидом из query или сохраненным
+	if c.Request.Body != nil && c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			respondBindError(c, err)
+			return req, false
+		}
+	}
+	if seedStr, ok := c.GetQuery("seed"); ok {
+		seed, err := strconv.ParseInt(seedStr, 10, 64)
+		if err != nil {
+			respondInvalidParameter(c, "seed")
+			return req, false
+		}
+		if req.Seed != nil && *req.Seed != seed {
+			problem(c, http.StatusBadRequest, ERROR_CODE_INVALID_PARAMETER, translate(requestLanguage(c), "seed %d in query conflicts with seed %d in body", seed, *req.Seed), nil)
+			return req, false
+		}
+		req.Seed = &seed
+	}
+	return req, true
+}
+
+func (t *TournamentHandler) ListTournaments(c *gin.Context) {
+	var req usecase.ListTournamentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	res, err := t.TournamentUsecase.ListTournaments(req)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (t *TournamentHandler) GetTournament(c *gin.Context) {
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
+	if err != nil {
+		respondInvalidParameter(c, "tournament_id")
+		return
+	}
+
+	res, err := t.TournamentUsecase.GetTournament(tournamentID)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (t *TournamentHandler) ListTeams(c *gin.Context) {
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
+	if err != nil {
+		respondInvalidParameter(c, "tournament_id")
+		return
+	}
+
+	var req usecase.ListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	res, err := t.TournamentUsecase.ListTeams(tournamentID, req)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (t *TournamentHandler) ListGames(c *gin.Context) {
+	tournamentIDStr := c.Param("id")
+
+	tournamentID, err := strconv.Atoi(tournamentIDStr)
+	if err != nil {
+		respondInvalidParameter(c, "tournament_id")
+		return
+	}
+
+	var req usecase.ListGamesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	res, err := t.TournamentUsecase.ListGames(tournamentID, req)
+
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
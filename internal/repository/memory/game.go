@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+)
+
+type GameRepository struct {
+	Store *Store
+	tx    *data
+}
+
+func NewGameRepository(store *Store) *GameRepository {
+	return &GameRepository{
+		Store: store,
+	}
+}
+
+func (g *GameRepository) Create(game entity.Game) (*entity.Game, error) {
+	err := g.Store.access(g.tx, func(d *data) error {
+		if _, ok := d.tournaments[game.TournamentID]; !ok {
+			return fmt.Errorf("tournament %d does not exist", game.TournamentID)
+		}
+		if d.gameSlotTaken(game) {
+			return usecase.ErrDuplicate
+		}
+		d.gameSeq++
+		game.ID = d.gameSeq
+		game.Maps = gameMaps(game)
+		d.games[game.ID] = game
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
+
+func (g *GameRepository) GetById(id int) (*entity.Game, error) {
+	var game entity.Game
+	err := g.Store.access(g.tx, func(d *data) error {
+		var ok bool
+		game, ok = d.games[id]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
+
+func (g *GameRepository) GetByTournament(tournamentID int) ([]entity.Game, error) {
+	return g.filter(func(game entity.Game) bool {
+		return game.TournamentID == tournamentID
+	})
+}
+
+func (g *GameRepository) GetByTypeGames(tournamentID int, gameType int) ([]entity.Game, error) {
+	return g.filter(func(game entity.Game) bool {
+		return game.TournamentID == tournamentID && game.GameType == gameType
+	})
+}
+
+func (g *GameRepository) List(tournamentID int, filter usecase.GameFilter, opts usecase.ListOptions) ([]entity.Game, int, error) {
+	games, err := g.filter(func(game entity.Game) bool {
+		return game.TournamentID == tournamentID &&
+			(filter.GameType == 0 || game.GameType == filter.GameType) &&
+			(filter.Status == 0 || game.Status == filter.Status)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if games == nil {
+		games = []entity.Game{}
+	}
+	return page(games, opts, gameCompare, func(g entity.Game) int { return g.ID }), len(games), nil
+}
+
+// Update сохраняет результат еще не сыгранного матча: победителя, статус, счет и карты.
+func (g *GameRepository) Update(game entity.Game) (*entity.Game, error) {
+	var updated entity.Game
+	err := g.Store.access(g.tx, func(d *data) error {
+		var ok bool
+		updated, ok = d.games[game.ID]
+		if !ok || updated.IsPlayed() {
+			return sql.ErrNoRows
+		}
+		updated.WinnerId = game.WinnerId
+		updated.Status = game.Status
+		updated.Team1Score = game.Team1Score
+		updated.Team2Score = game.Team2Score
+		updated.Maps = gameMaps(game)
+		d.games[game.ID] = updated
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// filter - матчи по условию в порядке создания.
+func (g *GameRepository) filter(match func(game entity.Game) bool) ([]entity.Game, error) {
+	var games []entity.Game
+	err := g.Store.access(g.tx, func(d *data) error {
+		for _, game := range d.games {
+			if match(game) {
+				games = append(games, game)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].ID < games[j].ID
+	})
+	return games, nil
+}
+
+// gameMaps - копия карт матча; как и в базе, отсутствие карт хранится пустым списком.
+func gameMaps(game entity.Game) []entity.GameMap {
+	if game.Maps == nil {
+		return []entity.GameMap{}
+	}
+	return slices.Clone(game.Maps)
+}
+
+// gameSlotTaken - в стадии турнира уже есть матч на этом месте сетки: тип, группа, раунд и позиция.
+func (d *data) gameSlotTaken(game entity.Game) bool {
+	for _, other := range d.games {
+		if other.TournamentID == game.TournamentID && other.GameType == game.GameType && other.Group == game.Group && other.Round == game.Round && other.Position == game.Position {
+			return true
+		}
+	}
+	return false
+}
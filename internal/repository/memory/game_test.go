@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+)
+
+func TestUpdateKeepsFinishedGame(t *testing.T) {
+	store := NewStore()
+	tournament, err := NewTournamentRepository(store).Create(entity.Tournament{Name: "Cup"})
+	if err != nil {
+		t.Fatalf("create tournament: %v", err)
+	}
+	games := NewGameRepository(store)
+	game, err := games.Create(entity.Game{TournamentID: tournament.ID, Team1ID: 1, Team2ID: 2, Status: entity.GAME_STATUS_SCHEDULED})
+	if err != nil {
+		t.Fatalf("create game: %v", err)
+	}
+
+	team1Score, team2Score := 1, 0
+	game.Team1Score, game.Team2Score = &team1Score, &team2Score
+	game.WinnerId = &game.Team1ID
+	game.Status = entity.GAME_STATUS_FINISHED
+	if _, err := games.Update(*game); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	game.WinnerId = &game.Team2ID
+	if _, err := games.Update(*game); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("update of a finished game: error %v, want sql.ErrNoRows", err)
+	}
+	if _, err := games.Update(entity.Game{ID: game.ID + 1}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("update of a missing game: error %v, want sql.ErrNoRows", err)
+	}
+	if got, err := games.GetById(game.ID); err != nil || *got.WinnerId != game.Team1ID {
+		t.Errorf("game after second update %+v, %v", got, err)
+	}
+}
+
+func TestGameSlotsAreUnique(t *testing.T) {
+	store := NewStore()
+	tournament, err := NewTournamentRepository(store).Create(entity.Tournament{Name: "Cup"})
+	if err != nil {
+		t.Fatalf("create tournament: %v", err)
+	}
+	games := NewGameRepository(store)
+	game := entity.Game{TournamentID: tournament.ID, Team1ID: 1, Team2ID: 2, GameType: entity.GAME_TYPE_GROUP, Group: 1, Round: 2, Position: 3}
+	if _, err := games.Create(game); err != nil {
+		t.Fatalf("create game: %v", err)
+	}
+	if _, err := games.Create(game); !errors.Is(err, usecase.ErrDuplicate) {
+		t.Errorf("same slot twice: error %v, want %v", err, usecase.ErrDuplicate)
+	}
+	game.Position++
+	if _, err := games.Create(game); err != nil {
+		t.Errorf("next position: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+)
+
+// page сортирует записи, как ORDER BY <поле>, id, и вырезает страницу, как LIMIT/OFFSET.
+// Поле, которого нет в compare, сортирует только по id.
+func page[T any](items []T, opts usecase.ListOptions, compare map[string]func(a, b T) int, id func(T) int) []T {
+	byField := compare[opts.Sort]
+	slices.SortFunc(items, func(a, b T) int {
+		c := 0
+		if byField != nil {
+			c = byField(a, b)
+		}
+		if c == 0 {
+			c = cmp.Compare(id(a), id(b))
+		}
+		if opts.Desc {
+			return -c
+		}
+		return c
+	})
+
+	if opts.Offset >= len(items) {
+		return items[:0]
+	}
+	return items[opts.Offset:min(opts.Offset+opts.Limit, len(items))]
+}
+
+var tournamentCompare = map[string]func(a, b entity.Tournament) int{
+	"name":   func(a, b entity.Tournament) int { return strings.Compare(a.Name, b.Name) },
+	"status": func(a, b entity.Tournament) int { return strings.Compare(a.Status, b.Status) },
+}
+
+var teamCompare = map[string]func(a, b entity.Team) int{
+	"name":   func(a, b entity.Team) int { return strings.Compare(a.Name, b.Name) },
+	"rating": func(a, b entity.Team) int { return cmp.Compare(a.Rating, b.Rating) },
+	"seed":   func(a, b entity.Team) int { return cmp.Compare(a.Seed, b.Seed) },
+}
+
+var gameCompare = map[string]func(a, b entity.Game) int{
+	"stage":     func(a, b entity.Game) int { return cmp.Compare(a.Stage, b.Stage) },
+	"round":     func(a, b entity.Game) int { return cmp.Compare(a.Round, b.Round) },
+	"game_type": func(a, b entity.Game) int { return cmp.Compare(a.GameType, b.GameType) },
+	"status":    func(a, b entity.Game) int { return cmp.Compare(a.Status, b.Status) },
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"maps"
+	"sync"
+	"tournament/internal/entity"
+)
+
+// data - содержимое хранилища. В транзакции работа идет с копией, которая заменяет данные при фиксации.
+type data struct {
+	tournaments map[int]entity.Tournament
+	teams       map[int]entity.Team
+	games       map[int]entity.Game
+	lots        map[int][]entity.Lot
+	placements  map[int][]entity.Placement
+
+	// последовательности идентификаторов, как SERIAL: значения не переиспользуются после удаления
+	tournamentSeq int
+	teamSeq       int
+	gameSeq       int
+}
+
+func (d *data) clone() *data {
+	c := *d
+	c.tournaments = maps.Clone(d.tournaments)
+	c.teams = maps.Clone(d.teams)
+	c.games = maps.Clone(d.games)
+	c.lots = maps.Clone(d.lots)
+	c.placements = maps.Clone(d.placements)
+	return &c
+}
+
+// Store - хранилище в памяти, общее для репозиториев. Безопасно для использования из нескольких горутин.
+type Store struct {
+	mu   sync.Mutex
+	data *data
+}
+
+func NewStore() *Store {
+	return &Store{
+		data: &data{
+			tournaments: map[int]entity.Tournament{},
+			teams:       map[int]entity.Team{},
+			games:       map[int]entity.Game{},
+			lots:        map[int][]entity.Lot{},
+			placements:  map[int][]entity.Placement{},
+		},
+	}
+}
+
+// access выполняет fn над данными: вне транзакции (tx == nil) под блокировкой хранилища,
+// в транзакции - над ее копией, блокировку которой уже держит UnitOfWork.
+func (s *Store) access(tx *data, fn func(d *data) error) error {
+	if tx != nil {
+		return fn(tx)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.data)
+}
@@ -0,0 +1,289 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+)
+
+type TournamentRepository struct {
+	Store *Store
+	tx    *data
+}
+
+func NewTournamentRepository(store *Store) *TournamentRepository {
+	return &TournamentRepository{
+		Store: store,
+	}
+}
+
+func (t *TournamentRepository) Create(tournament entity.Tournament) (*entity.Tournament, error) {
+	err := t.Store.access(t.tx, func(d *data) error {
+		d.tournamentSeq++
+		tournament.ID = d.tournamentSeq
+		d.tournaments[tournament.ID] = tournament
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
+
+// Delete удаляет турнир вместе с командами, матчами, жребием и местами, как ON DELETE CASCADE.
+func (t *TournamentRepository) Delete(tournament entity.Tournament) error {
+	return t.Store.access(t.tx, func(d *data) error {
+		delete(d.tournaments, tournament.ID)
+		for id, team := range d.teams {
+			if team.TournamentID == tournament.ID {
+				delete(d.teams, id)
+			}
+		}
+		for id, game := range d.games {
+			if game.TournamentID == tournament.ID {
+				delete(d.games, id)
+			}
+		}
+		delete(d.lots, tournament.ID)
+		delete(d.placements, tournament.ID)
+		return nil
+	})
+}
+
+func (t *TournamentRepository) GetById(id int) (*entity.Tournament, error) {
+	var tournament entity.Tournament
+	err := t.Store.access(t.tx, func(d *data) error {
+		var ok bool
+		tournament, ok = d.tournaments[id]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
+
+func (t *TournamentRepository) Update(tournament entity.Tournament) (*entity.Tournament, error) {
+	err := t.Store.access(t.tx, func(d *data) error {
+		// как UPDATE без подходящей строки: ничего не меняется и ошибки нет
+		if _, ok := d.tournaments[tournament.ID]; ok {
+			d.tournaments[tournament.ID] = tournament
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
+
+func (t *TournamentRepository) List(filter usecase.TournamentFilter, opts usecase.ListOptions) ([]entity.Tournament, int, error) {
+	tournaments := []entity.Tournament{}
+	err := t.Store.access(t.tx, func(d *data) error {
+		for _, tournament := range d.tournaments {
+			if filter.Status == "" || tournament.Status == filter.Status {
+				tournaments = append(tournaments, tournament)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return page(tournaments, opts, tournamentCompare, func(t entity.Tournament) int { return t.ID }), len(tournaments), nil
+}
+
+func (t *TournamentRepository) AddTeam(tournamentID int, team entity.Team) (*entity.Team, error) {
+	err := t.Store.access(t.tx, func(d *data) error {
+		if _, ok := d.tournaments[tournamentID]; !ok {
+			return fmt.Errorf("tournament %d does not exist", tournamentID)
+		}
+		team.TournamentID = tournamentID
+		if d.teamNameTaken(team) {
+			return usecase.ErrDuplicate
+		}
+		d.teamSeq++
+		team.ID = d.teamSeq
+		d.teams[team.ID] = team
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (t *TournamentRepository) GetTeam(id int) (*entity.Team, error) {
+	var team entity.Team
+	err := t.Store.access(t.tx, func(d *data) error {
+		var ok bool
+		team, ok = d.teams[id]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (t *TournamentRepository) UpdateTeam(team entity.Team) (*entity.Team, error) {
+	err := t.Store.access(t.tx, func(d *data) error {
+		if saved, ok := d.teams[team.ID]; ok {
+			team.TournamentID = saved.TournamentID
+			if d.teamNameTaken(team) {
+				return usecase.ErrDuplicate
+			}
+			saved.Name = team.Name
+			saved.Rating = team.Rating
+			saved.Seed = team.Seed
+			saved.Region = team.Region
+			d.teams[team.ID] = saved
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// DeleteTeam удаляет команду вместе с ее матчами, жребием и местами, как ON DELETE CASCADE.
+func (t *TournamentRepository) DeleteTeam(team entity.Team) error {
+	return t.Store.access(t.tx, func(d *data) error {
+		saved, ok := d.teams[team.ID]
+		if !ok {
+			return nil
+		}
+		delete(d.teams, team.ID)
+		for id, game := range d.games {
+			if game.Team1ID == team.ID || game.Team2ID == team.ID {
+				delete(d.games, id)
+			}
+		}
+		d.lots[saved.TournamentID] = slices.DeleteFunc(slices.Clone(d.lots[saved.TournamentID]), func(lot entity.Lot) bool {
+			return lot.TeamID == team.ID
+		})
+		d.placements[saved.TournamentID] = slices.DeleteFunc(slices.Clone(d.placements[saved.TournamentID]), func(placement entity.Placement) bool {
+			return placement.TeamID == team.ID
+		})
+		return nil
+	})
+}
+
+func (t *TournamentRepository) GetTeams(tournamentID int) ([]entity.Team, error) {
+	var teams []entity.Team
+	err := t.Store.access(t.tx, func(d *data) error {
+		for _, team := range d.teams {
+			if team.TournamentID == tournamentID {
+				teams = append(teams, team)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].ID < teams[j].ID
+	})
+	return teams, nil
+}
+
+func (t *TournamentRepository) ListTeams(tournamentID int, opts usecase.ListOptions) ([]entity.Team, int, error) {
+	teams := []entity.Team{}
+	err := t.Store.access(t.tx, func(d *data) error {
+		for _, team := range d.teams {
+			if team.TournamentID == tournamentID {
+				teams = append(teams, team)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return page(teams, opts, teamCompare, func(t entity.Team) int { return t.ID }), len(teams), nil
+}
+
+func (t *TournamentRepository) AddLots(tournamentID int, lots []entity.Lot) error {
+	return t.Store.access(t.tx, func(d *data) error {
+		saved := slices.Clone(d.lots[tournamentID])
+		for _, lot := range lots {
+			for _, existing := range saved {
+				if existing.TeamID == lot.TeamID {
+					return fmt.Errorf("lot for team %d is already drawn", lot.TeamID)
+				}
+			}
+			saved = append(saved, entity.Lot{TournamentID: tournamentID, TeamID: lot.TeamID, Value: lot.Value})
+		}
+		d.lots[tournamentID] = saved
+		return nil
+	})
+}
+
+func (t *TournamentRepository) GetLots(tournamentID int) ([]entity.Lot, error) {
+	var lots []entity.Lot
+	err := t.Store.access(t.tx, func(d *data) error {
+		lots = slices.Clone(d.lots[tournamentID])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(lots, func(i, j int) bool {
+		return lots[i].Value < lots[j].Value
+	})
+	return lots, nil
+}
+
+// SavePlacements заменяет итоговые места турнира.
+func (t *TournamentRepository) SavePlacements(tournamentID int, placements []entity.Placement) error {
+	return t.Store.access(t.tx, func(d *data) error {
+		var saved []entity.Placement
+		for _, placement := range placements {
+			placement.TournamentID = tournamentID
+			saved = append(saved, placement)
+		}
+		d.placements[tournamentID] = saved
+		return nil
+	})
+}
+
+func (t *TournamentRepository) GetPlacements(tournamentID int) ([]entity.Placement, error) {
+	var placements []entity.Placement
+	err := t.Store.access(t.tx, func(d *data) error {
+		placements = slices.Clone(d.placements[tournamentID])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(placements, func(i, j int) bool {
+		if placements[i].PlaceFrom != placements[j].PlaceFrom {
+			return placements[i].PlaceFrom < placements[j].PlaceFrom
+		}
+		return placements[i].TeamID < placements[j].TeamID
+	})
+	return placements, nil
+}
+
+// teamNameTaken - есть ли в турнире другая команда с таким же названием без учета регистра,
+// как уникальный индекс по (tournament_id, LOWER(name)).
+func (d *data) teamNameTaken(team entity.Team) bool {
+	for _, other := range d.teams {
+		if other.ID != team.ID && other.TournamentID == team.TournamentID && strings.EqualFold(other.Name, team.Name) {
+			return true
+		}
+	}
+	return false
+}
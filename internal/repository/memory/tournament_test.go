@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+)
+
+func TestTeamNamesAreUnique(t *testing.T) {
+	tournaments := NewTournamentRepository(NewStore())
+	tournament, err := tournaments.Create(entity.Tournament{Name: "Cup"})
+	if err != nil {
+		t.Fatalf("create tournament: %v", err)
+	}
+	other, err := tournaments.Create(entity.Tournament{Name: "Other cup"})
+	if err != nil {
+		t.Fatalf("create tournament: %v", err)
+	}
+
+	alpha, err := tournaments.AddTeam(tournament.ID, entity.Team{Name: "Alpha"})
+	if err != nil {
+		t.Fatalf("add team: %v", err)
+	}
+	bravo, err := tournaments.AddTeam(tournament.ID, entity.Team{Name: "Bravo"})
+	if err != nil {
+		t.Fatalf("add team: %v", err)
+	}
+
+	if _, err := tournaments.AddTeam(tournament.ID, entity.Team{Name: "ALPHA"}); !errors.Is(err, usecase.ErrDuplicate) {
+		t.Errorf("add duplicate: error %v, want %v", err, usecase.ErrDuplicate)
+	}
+	if _, err := tournaments.AddTeam(other.ID, entity.Team{Name: "Alpha"}); err != nil {
+		t.Errorf("same name in another tournament: %v", err)
+	}
+
+	bravo.Name = "alpha"
+	if _, err := tournaments.UpdateTeam(*bravo); !errors.Is(err, usecase.ErrDuplicate) {
+		t.Errorf("rename to a taken name: error %v, want %v", err, usecase.ErrDuplicate)
+	}
+	alpha.Name = "ALPHA"
+	if _, err := tournaments.UpdateTeam(*alpha); err != nil {
+		t.Errorf("rename to own name: %v", err)
+	}
+}
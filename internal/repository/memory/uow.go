@@ -0,0 +1,29 @@
+package memory
+
+import "tournament/internal/usecase"
+
+type UnitOfWork struct {
+	Store *Store
+}
+
+func NewUnitOfWork(store *Store) *UnitOfWork {
+	return &UnitOfWork{
+		Store: store,
+	}
+}
+
+// Do выполняет fn над копией данных и заменяет ими хранилище, только если fn завершилась без ошибки.
+// Хранилище заблокировано на все время транзакции (и вместе с ним турнир tournamentID),
+// поэтому fn должна использовать только переданные репозитории.
+func (u *UnitOfWork) Do(tournamentID int, fn func(tournamentRep usecase.TournamentRepository, gameRep usecase.GameRepository) error) error {
+	u.Store.mu.Lock()
+	defer u.Store.mu.Unlock()
+
+	tx := u.Store.data.clone()
+	err := fn(&TournamentRepository{Store: u.Store, tx: tx}, &GameRepository{Store: u.Store, tx: tx})
+	if err != nil {
+		return err
+	}
+	u.Store.data = tx
+	return nil
+}
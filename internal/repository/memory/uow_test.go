@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+)
+
+func TestUnitOfWorkRollsBack(t *testing.T) {
+	store := NewStore()
+	tournaments := NewTournamentRepository(store)
+	tournament, err := tournaments.Create(entity.Tournament{Name: "Cup"})
+	if err != nil {
+		t.Fatalf("create tournament: %v", err)
+	}
+
+	failure := errors.New("failure")
+	err = NewUnitOfWork(store).Do(tournament.ID, func(tournamentRep usecase.TournamentRepository, gameRep usecase.GameRepository) error {
+		if _, err := tournamentRep.AddTeam(tournament.ID, entity.Team{Name: "Team"}); err != nil {
+			return err
+		}
+		if _, err := gameRep.Create(entity.Game{TournamentID: tournament.ID, Team1ID: 1}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("error %v, want %v", err, failure)
+	}
+
+	teams, err := tournaments.GetTeams(tournament.ID)
+	if err != nil || len(teams) != 0 {
+		t.Errorf("teams after rollback: %v, %v", teams, err)
+	}
+	games, err := NewGameRepository(store).GetByTournament(tournament.ID)
+	if err != nil || len(games) != 0 {
+		t.Errorf("games after rollback: %v, %v", games, err)
+	}
+}
+
+func TestUnitOfWorkCommits(t *testing.T) {
+	store := NewStore()
+	var created *entity.Tournament
+	err := NewUnitOfWork(store).Do(0, func(tournamentRep usecase.TournamentRepository, gameRep usecase.GameRepository) error {
+		var err error
+		created, err = tournamentRep.Create(entity.Tournament{Name: "Cup"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+
+	if _, err := NewTournamentRepository(store).GetById(created.ID); err != nil {
+		t.Errorf("tournament after commit: %v", err)
+	}
+	if _, err := NewTournamentRepository(store).GetById(created.ID + 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("missing tournament: error %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestStoreIsSafeForConcurrentUse(t *testing.T) {
+	store := NewStore()
+	tournaments := NewTournamentRepository(store)
+	tournament, err := tournaments.Create(entity.Tournament{Name: "Cup"})
+	if err != nil {
+		t.Fatalf("create tournament: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := tournaments.AddTeam(tournament.ID, entity.Team{Name: fmt.Sprintf("Team %d", i)}); err != nil {
+				t.Errorf("add team: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	teams, err := tournaments.GetTeams(tournament.ID)
+	if err != nil {
+		t.Fatalf("teams: %v", err)
+	}
+	ids := map[int]bool{}
+	for _, team := range teams {
+		ids[team.ID] = true
+	}
+	if len(teams) != 50 || len(ids) != 50 {
+		t.Errorf("%d teams with %d distinct ids, want 50", len(teams), len(ids))
+	}
+}
@@ -0,0 +1,32 @@
+// Package pgsql - репозитории в PostgreSQL.
+package pgsql
+
+import (
+	"database/sql"
+	"errors"
+	"tournament/internal/repository/sqldb"
+
+	"github.com/lib/pq"
+)
+
+var Dialect = sqldb.Dialect{
+	Numbered: true,
+	// блокируется только строка турнира: шаги разных турниров идут параллельно
+	LockTournament: "SELECT id FROM tournaments WHERE id = ? FOR UPDATE",
+	IsDuplicate: func(err error) bool {
+		var pqErr *pq.Error
+		return errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation"
+	},
+}
+
+func NewTournamentRepository(db sqldb.DBTX) *sqldb.TournamentRepository {
+	return sqldb.NewTournamentRepository(db, Dialect)
+}
+
+func NewGameRepository(db sqldb.DBTX) *sqldb.GameRepository {
+	return sqldb.NewGameRepository(db, Dialect)
+}
+
+func NewUnitOfWork(db *sql.DB) *sqldb.UnitOfWork {
+	return sqldb.NewUnitOfWork(db, Dialect)
+}
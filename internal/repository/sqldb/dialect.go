@@ -0,0 +1,71 @@
+// Package sqldb - репозитории поверх database/sql, общие для PostgreSQL и SQLite.
+// Запросы пишутся с плейсхолдерами ?, а различия баз описывает Dialect.
+package sqldb
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"tournament/internal/usecase"
+)
+
+// Dialect - то, чем SQL базы отличается от общего.
+type Dialect struct {
+	// Numbered - плейсхолдеры нумеруются ($1, $2, ...), как в PostgreSQL, а не ?
+	Numbered bool
+	// LockTournament блокирует турнир (параметр - id) до конца транзакции
+	LockTournament string
+	// IsDuplicate сообщает, что ошибка - нарушение уникального индекса
+	IsDuplicate func(err error) bool
+}
+
+// rebind переводит плейсхолдеры ? в синтаксис базы. Знаков ? в самих запросах нет.
+func (d Dialect) rebind(query string) string {
+	if !d.Numbered {
+		return query
+	}
+	var result strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			result.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
+
+// duplicate заменяет нарушение уникального индекса на usecase.ErrDuplicate.
+func (d Dialect) duplicate(err error) error {
+	if err != nil && d.IsDuplicate(err) {
+		return usecase.ErrDuplicate
+	}
+	return err
+}
+
+// bind оборачивает db так, чтобы запросы переводились в синтаксис базы.
+func (d Dialect) bind(db DBTX) DBTX {
+	if !d.Numbered {
+		return db
+	}
+	return boundDB{db: db, dialect: d}
+}
+
+type boundDB struct {
+	db      DBTX
+	dialect Dialect
+}
+
+func (b boundDB) Exec(query string, args ...any) (sql.Result, error) {
+	return b.db.Exec(b.dialect.rebind(query), args...)
+}
+
+func (b boundDB) Query(query string, args ...any) (*sql.Rows, error) {
+	return b.db.Query(b.dialect.rebind(query), args...)
+}
+
+func (b boundDB) QueryRow(query string, args ...any) *sql.Row {
+	return b.db.QueryRow(b.dialect.rebind(query), args...)
+}
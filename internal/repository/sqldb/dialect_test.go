@@ -0,0 +1,14 @@
+package sqldb
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	query := "UPDATE games SET status = ? WHERE id = ? AND status <> ?"
+	numbered := Dialect{Numbered: true}
+	if got, want := numbered.rebind(query), "UPDATE games SET status = $1 WHERE id = $2 AND status <> $3"; got != want {
+		t.Errorf("numbered: %q, want %q", got, want)
+	}
+	if got := (Dialect{}).rebind(query); got != query {
+		t.Errorf("positional: %q, want %q", got, query)
+	}
+}
@@ -0,0 +1,203 @@
+package sqldb
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+)
+
+type GameRepository struct {
+	DB        DBTX
+	Dialect   Dialect
+	TableName string
+}
+
+const gameColumns = "id, tournament_id, team1_id, team2_id, game_type, stage, group_number, round, position, best_of, status, team1_score, team2_score, maps, winner_id, team1_source_game_id, team2_source_game_id"
+
+func NewGameRepository(db DBTX, dialect Dialect) *GameRepository {
+	return &GameRepository{
+		DB:        dialect.bind(db),
+		Dialect:   dialect,
+		TableName: "games",
+	}
+}
+
+func (g *GameRepository) Create(game entity.Game) (*entity.Game, error) {
+	maps, err := json.Marshal(gameMaps(game))
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (tournament_id, team1_id, team2_id, game_type, stage, group_number, round, position, best_of, status, team1_score, team2_score, maps, winner_id, team1_source_game_id, team2_source_game_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING %s
+	`, g.TableName, gameColumns)
+
+	row := g.DB.QueryRow(query, game.TournamentID, game.Team1ID, nullableID(game.Team2ID), game.GameType, game.Stage, game.Group, game.Round, game.Position, game.BestOf, game.Status, game.Team1Score, game.Team2Score, string(maps), game.WinnerId, nullableID(game.Team1SourceGameID), nullableID(game.Team2SourceGameID))
+	res, err := scanGame(row)
+	if err != nil {
+		return nil, g.Dialect.duplicate(err)
+	}
+	return res, nil
+}
+
+func (g *GameRepository) GetById(id int) (*entity.Game, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = ?", gameColumns, g.TableName)
+	return scanGame(g.DB.QueryRow(query, id))
+}
+
+func (g *GameRepository) GetByTournament(tournamentID int) ([]entity.Game, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s WHERE tournament_id = ?
+		ORDER BY id
+	`, gameColumns, g.TableName)
+
+	rows, err := g.DB.Query(query, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []entity.Game
+	for rows.Next() {
+		game, err := scanGame(rows)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, *game)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+func (g *GameRepository) GetByTypeGames(tournamentID int, gameType int) ([]entity.Game, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s WHERE tournament_id = ? AND game_type = ?
+		ORDER BY id
+	`, gameColumns, g.TableName)
+
+	rows, err := g.DB.Query(query, tournamentID, gameType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []entity.Game
+	for rows.Next() {
+		game, err := scanGame(rows)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, *game)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+var gameSortColumns = map[string]string{
+	"id":        "id",
+	"stage":     "stage",
+	"round":     "round",
+	"game_type": "game_type",
+	"status":    "status",
+}
+
+func (g *GameRepository) List(tournamentID int, filter usecase.GameFilter, opts usecase.ListOptions) ([]entity.Game, int, error) {
+	where := "tournament_id = ?"
+	args := []any{tournamentID}
+	if filter.GameType != 0 {
+		args = append(args, filter.GameType)
+		where += " AND game_type = ?"
+	}
+	if filter.Status != 0 {
+		args = append(args, filter.Status)
+		where += " AND status = ?"
+	}
+
+	var total int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", g.TableName, where)
+	if err := g.DB.QueryRow(query, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	order, args := orderBy(opts, gameSortColumns, args)
+	query = fmt.Sprintf("SELECT %s FROM %s WHERE %s %s", gameColumns, g.TableName, where, order)
+	rows, err := g.DB.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	games := []entity.Game{}
+	for rows.Next() {
+		game, err := scanGame(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		games = append(games, *game)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return games, total, nil
+}
+
+func (g *GameRepository) Update(game entity.Game) (*entity.Game, error) {
+	maps, err := json.Marshal(gameMaps(game))
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET winner_id = ?, status = ?, team1_score = ?, team2_score = ?, maps = ?
+		WHERE id = ? AND status <> ?
+		RETURNING %s
+	`, g.TableName, gameColumns)
+
+	return scanGame(g.DB.QueryRow(query, game.WinnerId, game.Status, game.Team1Score, game.Team2Score, string(maps), game.ID, entity.GAME_STATUS_FINISHED))
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanGame(row rowScanner) (*entity.Game, error) {
+	game := entity.Game{}
+	var team2ID, team1Source, team2Source sql.NullInt64
+	var maps []byte
+	err := row.Scan(&game.ID, &game.TournamentID, &game.Team1ID, &team2ID, &game.GameType, &game.Stage, &game.Group, &game.Round, &game.Position, &game.BestOf, &game.Status, &game.Team1Score, &game.Team2Score, &maps, &game.WinnerId, &team1Source, &team2Source)
+	if err != nil {
+		return nil, err
+	}
+	game.Team2ID = int(team2ID.Int64)
+	game.Team1SourceGameID = int(team1Source.Int64)
+	game.Team2SourceGameID = int(team2Source.Int64)
+	if err := json.Unmarshal(maps, &game.Maps); err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
+
+func gameMaps(game entity.Game) []entity.GameMap {
+	if game.Maps == nil {
+		return []entity.GameMap{}
+	}
+	return game.Maps
+}
+
+// nullableID превращает нулевой идентификатор в NULL
+func nullableID(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
@@ -0,0 +1,22 @@
+package sqldb
+
+import (
+	"fmt"
+	"tournament/internal/usecase"
+)
+
+// orderBy собирает ORDER BY и LIMIT/OFFSET для страницы списка. Поле сортировки
+// уже проверено в usecase, columns дополнительно защищает запрос от подстановки.
+func orderBy(opts usecase.ListOptions, columns map[string]string, args []any) (string, []any) {
+	column, ok := columns[opts.Sort]
+	if !ok {
+		column = "id"
+	}
+	direction := "ASC"
+	if opts.Desc {
+		direction = "DESC"
+	}
+
+	args = append(args, opts.Limit, opts.Offset)
+	return fmt.Sprintf("ORDER BY %s %s, id %s LIMIT ? OFFSET ?", column, direction, direction), args
+}
@@ -0,0 +1,301 @@
+package sqldb
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+)
+
+type TournamentRepository struct {
+	DB        DBTX
+	Dialect   Dialect
+	TableName string
+}
+
+func NewTournamentRepository(db DBTX, dialect Dialect) *TournamentRepository {
+	return &TournamentRepository{
+		DB:        dialect.bind(db),
+		Dialect:   dialect,
+		TableName: "tournaments",
+	}
+}
+
+func (t *TournamentRepository) Create(tournament entity.Tournament) (*entity.Tournament, error) {
+	settings, err := json.Marshal(tournament.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (name, format, status, settings) VALUES (?, ?, ?, ?) RETURNING id", t.TableName)
+	err = t.DB.QueryRow(query, tournament.Name, tournament.Format, tournament.Status, string(settings)).Scan(&tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
+
+func (t *TournamentRepository) Delete(tournament entity.Tournament) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ?", t.TableName)
+	_, err := t.DB.Exec(query, tournament.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+const tournamentColumns = "id, name, format, status, settings, seed"
+
+var tournamentSortColumns = map[string]string{
+	"id":     "id",
+	"name":   "name",
+	"status": "status",
+}
+
+func (t *TournamentRepository) GetById(id int) (*entity.Tournament, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = ?", tournamentColumns, t.TableName)
+	return scanTournament(t.DB.QueryRow(query, id))
+}
+
+func (t *TournamentRepository) List(filter usecase.TournamentFilter, opts usecase.ListOptions) ([]entity.Tournament, int, error) {
+	where := "TRUE"
+	var args []any
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where += " AND status = ?"
+	}
+
+	var total int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", t.TableName, where)
+	if err := t.DB.QueryRow(query, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	order, args := orderBy(opts, tournamentSortColumns, args)
+	query = fmt.Sprintf("SELECT %s FROM %s WHERE %s %s", tournamentColumns, t.TableName, where, order)
+	rows, err := t.DB.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	tournaments := []entity.Tournament{}
+	for rows.Next() {
+		tournament, err := scanTournament(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		tournaments = append(tournaments, *tournament)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return tournaments, total, nil
+}
+
+func (t *TournamentRepository) Update(tournament entity.Tournament) (*entity.Tournament, error) {
+	settings, err := json.Marshal(tournament.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET name = ?, format = ?, status = ?, settings = ?, seed = ? WHERE id = ?", t.TableName)
+	_, err = t.DB.Exec(query, tournament.Name, tournament.Format, tournament.Status, string(settings), tournament.Seed, tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
+
+func (t *TournamentRepository) AddTeam(tournamentID int, team entity.Team) (*entity.Team, error) {
+	query := "INSERT INTO teams (tournament_id, name, rating, seed, region) VALUES (?, ?, ?, ?, ?) RETURNING id"
+	err := t.DB.QueryRow(query, tournamentID, team.Name, team.Rating, team.Seed, team.Region).Scan(&team.ID)
+	if err != nil {
+		return nil, t.Dialect.duplicate(err)
+	}
+	team.TournamentID = tournamentID
+	return &team, nil
+}
+
+func (t *TournamentRepository) GetTeam(id int) (*entity.Team, error) {
+	query := fmt.Sprintf("SELECT %s FROM teams WHERE id = ?", teamColumns)
+	return scanTeam(t.DB.QueryRow(query, id))
+}
+
+func (t *TournamentRepository) UpdateTeam(team entity.Team) (*entity.Team, error) {
+	query := "UPDATE teams SET name = ?, rating = ?, seed = ?, region = ? WHERE id = ?"
+	_, err := t.DB.Exec(query, team.Name, team.Rating, team.Seed, team.Region, team.ID)
+	if err != nil {
+		return nil, t.Dialect.duplicate(err)
+	}
+	return &team, nil
+}
+
+func (t *TournamentRepository) DeleteTeam(team entity.Team) error {
+	_, err := t.DB.Exec("DELETE FROM teams WHERE id = ?", team.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *TournamentRepository) GetTeams(tournamentID int) ([]entity.Team, error) {
+	query := fmt.Sprintf("SELECT %s FROM teams WHERE tournament_id = ? ORDER BY id", teamColumns)
+	rows, err := t.DB.Query(query, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []entity.Team
+	for rows.Next() {
+		team, err := scanTeam(rows)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, *team)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+const teamColumns = "id, tournament_id, name, rating, seed, region"
+
+var teamSortColumns = map[string]string{
+	"id":     "id",
+	"name":   "name",
+	"rating": "rating",
+	"seed":   "seed",
+}
+
+func (t *TournamentRepository) ListTeams(tournamentID int, opts usecase.ListOptions) ([]entity.Team, int, error) {
+	var total int
+	err := t.DB.QueryRow("SELECT COUNT(*) FROM teams WHERE tournament_id = ?", tournamentID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	order, args := orderBy(opts, teamSortColumns, []any{tournamentID})
+	query := fmt.Sprintf("SELECT %s FROM teams WHERE tournament_id = ? %s", teamColumns, order)
+	rows, err := t.DB.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	teams := []entity.Team{}
+	for rows.Next() {
+		team, err := scanTeam(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		teams = append(teams, *team)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return teams, total, nil
+}
+
+func (t *TournamentRepository) AddLots(tournamentID int, lots []entity.Lot) error {
+	query := "INSERT INTO lots (tournament_id, team_id, value) VALUES (?, ?, ?)"
+	for _, lot := range lots {
+		_, err := t.DB.Exec(query, tournamentID, lot.TeamID, lot.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *TournamentRepository) GetLots(tournamentID int) ([]entity.Lot, error) {
+	query := "SELECT tournament_id, team_id, value FROM lots WHERE tournament_id = ? ORDER BY value"
+	rows, err := t.DB.Query(query, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []entity.Lot
+	for rows.Next() {
+		lot := entity.Lot{}
+		err := rows.Scan(&lot.TournamentID, &lot.TeamID, &lot.Value)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, lot)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+// SavePlacements заменяет итоговые места турнира.
+func (t *TournamentRepository) SavePlacements(tournamentID int, placements []entity.Placement) error {
+	_, err := t.DB.Exec("DELETE FROM placements WHERE tournament_id = ?", tournamentID)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO placements (tournament_id, team_id, place_from, place_to) VALUES (?, ?, ?, ?)"
+	for _, placement := range placements {
+		_, err := t.DB.Exec(query, tournamentID, placement.TeamID, placement.PlaceFrom, placement.PlaceTo)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *TournamentRepository) GetPlacements(tournamentID int) ([]entity.Placement, error) {
+	query := "SELECT tournament_id, team_id, place_from, place_to FROM placements WHERE tournament_id = ? ORDER BY place_from, team_id"
+	rows, err := t.DB.Query(query, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var placements []entity.Placement
+	for rows.Next() {
+		placement := entity.Placement{}
+		err := rows.Scan(&placement.TournamentID, &placement.TeamID, &placement.PlaceFrom, &placement.PlaceTo)
+		if err != nil {
+			return nil, err
+		}
+		placements = append(placements, placement)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return placements, nil
+}
+
+func scanTournament(row rowScanner) (*entity.Tournament, error) {
+	tournament := entity.Tournament{}
+	var settings []byte
+	var seed sql.NullInt64
+	err := row.Scan(&tournament.ID, &tournament.Name, &tournament.Format, &tournament.Status, &settings, &seed)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(settings, &tournament.Settings); err != nil {
+		return nil, err
+	}
+	if seed.Valid {
+		tournament.Seed = &seed.Int64
+	}
+	return &tournament, nil
+}
+
+func scanTeam(row rowScanner) (*entity.Team, error) {
+	team := entity.Team{}
+	err := row.Scan(&team.ID, &team.TournamentID, &team.Name, &team.Rating, &team.Seed, &team.Region)
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
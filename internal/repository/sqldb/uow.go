@@ -0,0 +1,54 @@
+package sqldb
+
+import (
+	"database/sql"
+	"tournament/internal/usecase"
+)
+
+// DBTX - общее у *sql.DB и *sql.Tx: репозитории работают одинаково и вне транзакции, и внутри нее.
+type DBTX interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+type UnitOfWork struct {
+	DB      *sql.DB
+	Dialect Dialect
+}
+
+func NewUnitOfWork(db *sql.DB, dialect Dialect) *UnitOfWork {
+	return &UnitOfWork{
+		DB:      db,
+		Dialect: dialect,
+	}
+}
+
+// Do выполняет fn в транзакции: при ошибке или панике изменения откатываются.
+func (u *UnitOfWork) Do(tournamentID int, fn func(tournamentRep usecase.TournamentRepository, gameRep usecase.GameRepository) error) (err error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// турнир заблокирован до конца транзакции: параллельный шаг того же турнира ждет ее завершения
+	_, err = u.Dialect.bind(tx).Exec(u.Dialect.LockTournament, tournamentID)
+	if err != nil {
+		return err
+	}
+
+	err = fn(NewTournamentRepository(tx, u.Dialect), NewGameRepository(tx, u.Dialect))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,437 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+
+	"github.com/golang-migrate/migrate/v4"
+	sqlitemigrate "github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// newDB создает базу во временном каталоге и накатывает на нее миграции SQLite.
+func newDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, m := newMigrate(t)
+	db.SetMaxOpenConns(1)
+	if err := m.Up(); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	return db
+}
+
+// newMigrate создает пустую базу во временном каталоге и возвращает ее вместе с миграциями SQLite.
+func newMigrate(t *testing.T) (*sql.DB, *migrate.Migrate) {
+	t.Helper()
+	dsn, err := DSN(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("dsn: %v", err)
+	}
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	driver, err := sqlitemigrate.WithInstance(db, &sqlitemigrate.Config{})
+	if err != nil {
+		t.Fatalf("migrate driver: %v", err)
+	}
+	m, err := migrate.NewWithDatabaseInstance("file://../../../db/migrations/sqlite", "sqlite", driver)
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db, m
+}
+
+func TestTournamentRepository(t *testing.T) {
+	repo := NewTournamentRepository(newDB(t))
+
+	seed := int64(42)
+	tournament, err := repo.Create(entity.Tournament{
+		Name:     "Cup",
+		Format:   "swiss",
+		Status:   entity.TOURNAMENT_STATUS_REGISTRATION,
+		Settings: entity.TournamentSettings{BestOf: map[string]int{"final": 3}},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	tournament.Seed = &seed
+	tournament.Status = entity.TOURNAMENT_STATUS_SEEDING
+	if _, err := repo.Update(*tournament); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got, err := repo.GetById(tournament.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !reflect.DeepEqual(got, tournament) {
+		t.Errorf("got %+v, want %+v", got, tournament)
+	}
+
+	team1, err := repo.AddTeam(tournament.ID, entity.Team{Name: "A", Rating: 1600})
+	if err != nil {
+		t.Fatalf("add team: %v", err)
+	}
+	team2, err := repo.AddTeam(tournament.ID, entity.Team{Name: "B", Rating: 1400})
+	if err != nil {
+		t.Fatalf("add team: %v", err)
+	}
+	teams, err := repo.GetTeams(tournament.ID)
+	if err != nil || !reflect.DeepEqual(teams, []entity.Team{*team1, *team2}) {
+		t.Errorf("teams %+v, %v", teams, err)
+	}
+
+	lots := []entity.Lot{{TournamentID: tournament.ID, TeamID: team2.ID, Value: 1}, {TournamentID: tournament.ID, TeamID: team1.ID, Value: 2}}
+	if err := repo.AddLots(tournament.ID, lots); err != nil {
+		t.Fatalf("add lots: %v", err)
+	}
+	if got, err := repo.GetLots(tournament.ID); err != nil || !reflect.DeepEqual(got, lots) {
+		t.Errorf("lots %+v, %v", got, err)
+	}
+
+	// повторное сохранение мест заменяет прежние
+	placements := []entity.Placement{{TournamentID: tournament.ID, TeamID: team1.ID, PlaceFrom: 1, PlaceTo: 2}, {TournamentID: tournament.ID, TeamID: team2.ID, PlaceFrom: 1, PlaceTo: 2}}
+	if err := repo.SavePlacements(tournament.ID, placements); err != nil {
+		t.Fatalf("save placements: %v", err)
+	}
+	placements = []entity.Placement{{TournamentID: tournament.ID, TeamID: team1.ID, PlaceFrom: 1, PlaceTo: 1}, {TournamentID: tournament.ID, TeamID: team2.ID, PlaceFrom: 2, PlaceTo: 2}}
+	if err := repo.SavePlacements(tournament.ID, placements); err != nil {
+		t.Fatalf("save placements again: %v", err)
+	}
+	if got, err := repo.GetPlacements(tournament.ID); err != nil || !reflect.DeepEqual(got, placements) {
+		t.Errorf("placements %+v, %v", got, err)
+	}
+
+	if err := repo.Delete(*tournament); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repo.GetById(tournament.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted tournament: error %v, want sql.ErrNoRows", err)
+	}
+	if teams, err := repo.GetTeams(tournament.ID); err != nil || len(teams) != 0 {
+		t.Errorf("teams of deleted tournament %+v, %v", teams, err)
+	}
+}
+
+func TestGameRepository(t *testing.T) {
+	db := newDB(t)
+	tournaments := NewTournamentRepository(db)
+	games := NewGameRepository(db)
+
+	tournament, err := tournaments.Create(entity.Tournament{Name: "Cup", Format: "single_elimination", Status: entity.TOURNAMENT_STATUS_PLAYOFFS})
+	if err != nil {
+		t.Fatalf("create tournament: %v", err)
+	}
+	team1, _ := tournaments.AddTeam(tournament.ID, entity.Team{Name: "A"})
+	team2, _ := tournaments.AddTeam(tournament.ID, entity.Team{Name: "B"})
+	team3, _ := tournaments.AddTeam(tournament.ID, entity.Team{Name: "C"})
+
+	game, err := games.Create(entity.Game{TournamentID: tournament.ID, Team1ID: team1.ID, Team2ID: team2.ID, GameType: entity.GAME_TYPE_PLAYOFF_SEMIFINAL, Stage: 1, Round: 1, BestOf: 3, Status: entity.GAME_STATUS_SCHEDULED})
+	if err != nil {
+		t.Fatalf("create game: %v", err)
+	}
+	// у пропуска (bye) нет второй команды
+	bye, err := games.Create(entity.Game{TournamentID: tournament.ID, Team1ID: team3.ID, GameType: entity.GAME_TYPE_PLAYOFF_SEMIFINAL, Stage: 1, Round: 1, Position: 1, Status: entity.GAME_STATUS_FINISHED, WinnerId: &team3.ID})
+	if err != nil {
+		t.Fatalf("create bye: %v", err)
+	}
+	if bye.Team2ID != 0 || !bye.IsBye() {
+		t.Errorf("bye has second team %d", bye.Team2ID)
+	}
+
+	team1Score, team2Score := 2, 1
+	game.Team1Score, game.Team2Score = &team1Score, &team2Score
+	game.Maps = []entity.GameMap{{Team1Score: 16, Team2Score: 10}, {Team1Score: 8, Team2Score: 16}, {Team1Score: 16, Team2Score: 14}}
+	game.WinnerId = &team1.ID
+	game.Status = entity.GAME_STATUS_FINISHED
+	updated, err := games.Update(*game)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if !reflect.DeepEqual(updated, game) {
+		t.Errorf("updated %+v, want %+v", updated, game)
+	}
+	if got, err := games.GetById(game.ID); err != nil || !reflect.DeepEqual(got, game) {
+		t.Errorf("get %+v, %v", got, err)
+	}
+	// на месте сетки один матч
+	if _, err := games.Create(entity.Game{TournamentID: tournament.ID, Team1ID: team3.ID, Team2ID: team2.ID, GameType: entity.GAME_TYPE_PLAYOFF_SEMIFINAL, Stage: 1, Round: 1, Position: 1}); !errors.Is(err, usecase.ErrDuplicate) {
+		t.Errorf("game in a taken slot: error %v, want %v", err, usecase.ErrDuplicate)
+	}
+
+	// результат завершенного матча не перезаписывается
+	team1Score = 0
+	if _, err := games.Update(*game); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("update of a finished game: error %v, want sql.ErrNoRows", err)
+	}
+	team1Score = 2
+
+	final, err := games.Create(entity.Game{TournamentID: tournament.ID, Team1ID: team1.ID, Team2ID: team3.ID, GameType: entity.GAME_TYPE_PLAYOFF_FINAL, Stage: 2, Round: 2, Status: entity.GAME_STATUS_SCHEDULED, Team1SourceGameID: game.ID, Team2SourceGameID: bye.ID})
+	if err != nil {
+		t.Fatalf("create final: %v", err)
+	}
+	if got, err := games.GetById(final.ID); err != nil || got.Team1SourceGameID != game.ID || got.Team2SourceGameID != bye.ID {
+		t.Errorf("final %+v, want source games %d and %d: %v", got, game.ID, bye.ID, err)
+	}
+
+	all, err := games.GetByTournament(tournament.ID)
+	if err != nil || len(all) != 3 || all[0].ID != game.ID || all[1].ID != bye.ID || all[2].ID != final.ID {
+		t.Errorf("games of tournament %+v, %v", all, err)
+	}
+	if semifinals, err := games.GetByTypeGames(tournament.ID, entity.GAME_TYPE_PLAYOFF_SEMIFINAL); err != nil || len(semifinals) != 2 {
+		t.Errorf("semifinals %+v, %v", semifinals, err)
+	}
+	if finals, err := games.GetByTypeGames(tournament.ID, entity.GAME_TYPE_PLAYOFF_FINAL); err != nil || len(finals) != 1 {
+		t.Errorf("finals %+v, %v", finals, err)
+	}
+	if _, err := games.GetById(final.ID + 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("missing game: error %v, want sql.ErrNoRows", err)
+	}
+
+	// матчи удаляются вместе с турниром
+	if err := tournaments.Delete(*tournament); err != nil {
+		t.Fatalf("delete tournament: %v", err)
+	}
+	if all, err := games.GetByTournament(tournament.ID); err != nil || len(all) != 0 {
+		t.Errorf("games of deleted tournament %+v, %v", all, err)
+	}
+}
+
+func TestUnitOfWorkRollsBack(t *testing.T) {
+	db := newDB(t)
+	tournaments := NewTournamentRepository(db)
+	tournament, err := tournaments.Create(entity.Tournament{Name: "Cup", Format: "classic", Status: entity.TOURNAMENT_STATUS_REGISTRATION})
+	if err != nil {
+		t.Fatalf("create tournament: %v", err)
+	}
+
+	failure := errors.New("failure")
+	err = NewUnitOfWork(db).Do(tournament.ID, func(tournamentRep usecase.TournamentRepository, gameRep usecase.GameRepository) error {
+		if _, err := tournamentRep.AddTeam(tournament.ID, entity.Team{Name: "A"}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("error %v, want %v", err, failure)
+	}
+	if teams, err := tournaments.GetTeams(tournament.ID); err != nil || len(teams) != 0 {
+		t.Errorf("teams after rollback %+v, %v", teams, err)
+	}
+
+	err = NewUnitOfWork(db).Do(tournament.ID, func(tournamentRep usecase.TournamentRepository, gameRep usecase.GameRepository) error {
+		_, err := tournamentRep.AddTeam(tournament.ID, entity.Team{Name: "A"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+	if teams, err := tournaments.GetTeams(tournament.ID); err != nil || len(teams) != 1 {
+		t.Errorf("teams after commit %+v, %v", teams, err)
+	}
+}
+
+func TestRunTournament(t *testing.T) {
+	db := newDB(t)
+	uc := usecase.NewTournamentUsecase(NewTournamentRepository(db), NewGameRepository(db), NewUnitOfWork(db), usecase.NewEloSimulator())
+
+	created, err := uc.CreateTournament(usecase.CreateTournamentRequest{Name: "Cup", Format: "double_elimination"})
+	if err != nil {
+		t.Fatalf("create tournament: %v", err)
+	}
+	for _, name := range []string{"Alpha", "Bravo", "Charlie", "Delta", "Echo"} {
+		if _, err := uc.AddTeam(created.Tournament.ID, usecase.AddTeamRequest{Name: name}); err != nil {
+			t.Fatalf("add team %s: %v", name, err)
+		}
+	}
+
+	result, err := uc.RunTournament(created.Tournament.ID, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("run tournament: %v", err)
+	}
+	if len(result.Placements) != 5 {
+		t.Errorf("%d placements, want 5", len(result.Placements))
+	}
+	if saved, err := uc.GetTournamentResult(created.Tournament.ID); err != nil || !reflect.DeepEqual(saved, result) {
+		t.Errorf("saved result %+v differs from %+v: %v", saved, result, err)
+	}
+}
+
+func TestList(t *testing.T) {
+	db := newDB(t)
+	tournaments := NewTournamentRepository(db)
+	games := NewGameRepository(db)
+
+	for _, status := range []string{entity.TOURNAMENT_STATUS_REGISTRATION, entity.TOURNAMENT_STATUS_PLAYOFFS, entity.TOURNAMENT_STATUS_REGISTRATION} {
+		if _, err := tournaments.Create(entity.Tournament{Name: "Cup", Format: "classic", Status: status}); err != nil {
+			t.Fatalf("create tournament: %v", err)
+		}
+	}
+	list, total, err := tournaments.List(usecase.TournamentFilter{Status: entity.TOURNAMENT_STATUS_REGISTRATION}, usecase.ListOptions{Limit: 1, Offset: 1})
+	if err != nil || total != 2 || len(list) != 1 || list[0].ID != 3 {
+		t.Errorf("second tournament in registration %+v, total %d: %v", list, total, err)
+	}
+
+	for i, rating := range []int{1500, 1700, 1500, 1600} {
+		if _, err := tournaments.AddTeam(1, entity.Team{Name: fmt.Sprintf("Team %d", i+1), Rating: rating}); err != nil {
+			t.Fatalf("add team: %v", err)
+		}
+	}
+	teams, total, err := tournaments.ListTeams(1, usecase.ListOptions{Sort: "rating", Desc: true, Limit: 3})
+	if err != nil || total != 4 || len(teams) != 3 {
+		t.Fatalf("teams by rating %+v, total %d: %v", teams, total, err)
+	}
+	// при равенстве рейтинга порядок по id в том же направлении
+	if ids := []int{teams[0].ID, teams[1].ID, teams[2].ID}; !reflect.DeepEqual(ids, []int{2, 4, 3}) {
+		t.Errorf("teams by rating %v, want [2 4 3]", ids)
+	}
+
+	for round := 1; round <= 3; round++ {
+		status := entity.GAME_STATUS_SCHEDULED
+		if round == 1 {
+			status = entity.GAME_STATUS_FINISHED
+		}
+		if _, err := games.Create(entity.Game{TournamentID: 1, Team1ID: 1, Team2ID: 2, GameType: entity.GAME_TYPE_SWISS, Round: round, Status: status}); err != nil {
+			t.Fatalf("create game: %v", err)
+		}
+	}
+	scheduled, total, err := games.List(1, usecase.GameFilter{GameType: entity.GAME_TYPE_SWISS, Status: entity.GAME_STATUS_SCHEDULED}, usecase.ListOptions{Sort: "round", Desc: true, Limit: 10})
+	if err != nil || total != 2 || len(scheduled) != 2 || scheduled[0].Round != 3 || scheduled[1].Round != 2 {
+		t.Errorf("scheduled swiss games %+v, total %d: %v", scheduled, total, err)
+	}
+}
+
+func TestUniqueTeamNameMigration(t *testing.T) {
+	db, m := newMigrate(t)
+	if err := m.Migrate(20261018230000); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	// дубли, зарегистрированные до уникального индекса
+	if _, err := db.Exec(`INSERT INTO tournaments (name) VALUES ('Cup'), ('Other cup')`); err != nil {
+		t.Fatalf("insert tournaments: %v", err)
+	}
+	// "alpha #2" занимает название, которое получил бы второй дубль, а длинное название нужно обрезать под суффикс
+	long := strings.Repeat("x", 64)
+	_, err := db.Exec(`INSERT INTO teams (tournament_id, name) VALUES (1, 'Alpha'), (1, 'ALPHA'), (1, 'Bravo'), (1, 'alpha'), (2, 'Alpha'), (1, 'alpha #2'), (1, ?), (1, ?)`, long, strings.ToUpper(long))
+	if err != nil {
+		t.Fatalf("insert teams: %v", err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	repo := NewTournamentRepository(db)
+	teams, err := repo.GetTeams(1)
+	if err != nil {
+		t.Fatalf("get teams: %v", err)
+	}
+	var names []string
+	for _, team := range teams {
+		names = append(names, team.Name)
+	}
+	want := []string{"Alpha", "ALPHA #2-2", "Bravo", "alpha #4", "alpha #2", long, strings.ToUpper(long[:61]) + " #8"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("names %q, want %q", names, want)
+	}
+
+	if _, err := repo.AddTeam(1, entity.Team{Name: "bravo"}); !errors.Is(err, usecase.ErrDuplicate) {
+		t.Errorf("add duplicate: error %v, want %v", err, usecase.ErrDuplicate)
+	}
+	teams[2].Name = "ALPHA"
+	if _, err := repo.UpdateTeam(teams[2]); !errors.Is(err, usecase.ErrDuplicate) {
+		t.Errorf("rename to a taken name: error %v, want %v", err, usecase.ErrDuplicate)
+	}
+}
+
+func TestConcurrentRunTournament(t *testing.T) {
+	// у каждого запуска свое соединение, как у параллельных запросов к сервису
+	db, m := newMigrate(t)
+	if err := m.Up(); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	uc := usecase.NewTournamentUsecase(NewTournamentRepository(db), NewGameRepository(db), NewUnitOfWork(db), usecase.NewEloSimulator())
+
+	created, err := uc.CreateTournament(usecase.CreateTournamentRequest{Name: "Cup", Format: "swiss"})
+	if err != nil {
+		t.Fatalf("create tournament: %v", err)
+	}
+	for i := 0; i < 8; i++ {
+		if _, err := uc.AddTeam(created.Tournament.ID, usecase.AddTeamRequest{Name: fmt.Sprintf("Team %d", i)}); err != nil {
+			t.Fatalf("add team: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := uc.RunTournament(created.Tournament.ID, usecase.RunTournamentRequest{}); err != nil {
+				var domainErr *usecase.Error
+				if !errors.As(err, &domainErr) || domainErr.Code != usecase.ERROR_CODE_TOURNAMENT_FINISHED {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("parallel run: %v", err)
+	}
+
+	result, err := uc.GetTournamentResult(created.Tournament.ID)
+	if err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	// 8 команд играют 3 тура по 4 матча
+	if len(result.Placements) != 8 || len(result.Games) != 12 {
+		t.Errorf("%d placements and %d games, want 8 and 12", len(result.Placements), len(result.Games))
+	}
+}
+
+func TestDSN(t *testing.T) {
+	dir := t.TempDir()
+	// путь со своими параметрами и URI file: дополняются параметрами, а не ломаются
+	paths := []string{
+		filepath.Join(dir, "plain.db"),
+		filepath.Join(dir, "query.db") + "?_txlock=immediate",
+		"file:" + filepath.Join(dir, "uri.db") + "?mode=rwc",
+	}
+	for _, path := range paths {
+		dsn, err := DSN(path)
+		if err != nil {
+			t.Fatalf("%s: dsn: %v", path, err)
+		}
+		db, err := sql.Open("sqlite", dsn)
+		if err != nil {
+			t.Fatalf("%s: open: %v", path, err)
+		}
+		var foreignKeys, busyTimeout int
+		if err := db.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+			t.Fatalf("%s (%s): foreign_keys: %v", path, dsn, err)
+		}
+		if err := db.QueryRow("PRAGMA busy_timeout").Scan(&busyTimeout); err != nil {
+			t.Fatalf("%s (%s): busy_timeout: %v", path, dsn, err)
+		}
+		if foreignKeys != 1 || busyTimeout != 5000 {
+			t.Errorf("%s (%s): foreign_keys %d, busy_timeout %d", path, dsn, foreignKeys, busyTimeout)
+		}
+		db.Close()
+	}
+
+	if _, err := DSN("test.db?mode=%zz"); err == nil {
+		t.Error("malformed query: no error")
+	}
+}
@@ -0,0 +1,50 @@
+// Package sqlite - репозитории в SQLite для запуска без PostgreSQL.
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"tournament/internal/repository/sqldb"
+
+	sqlitedriver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+var Dialect = sqldb.Dialect{
+	// SQLite блокирует базу целиком, а не строку: запись в начале транзакции сразу берет блокировку записи,
+	// и параллельный шаг ждет ее завершения, а не читает состояние, которое эта транзакция изменит
+	LockTournament: "UPDATE tournaments SET id = id WHERE id = ?",
+	IsDuplicate: func(err error) bool {
+		var sqliteErr *sqlitedriver.Error
+		return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+	},
+}
+
+func NewTournamentRepository(db sqldb.DBTX) *sqldb.TournamentRepository {
+	return sqldb.NewTournamentRepository(db, Dialect)
+}
+
+func NewGameRepository(db sqldb.DBTX) *sqldb.GameRepository {
+	return sqldb.NewGameRepository(db, Dialect)
+}
+
+func NewUnitOfWork(db *sql.DB) *sqldb.UnitOfWork {
+	return sqldb.NewUnitOfWork(db, Dialect)
+}
+
+// DSN дополняет путь к базе (файл или URI file:, в том числе со своими параметрами) параметрами,
+// без которых репозитории работают неверно: без foreign_keys SQLite не выполняет ON DELETE CASCADE,
+// а без busy_timeout параллельная транзакция сразу получает SQLITE_BUSY.
+func DSN(path string) (string, error) {
+	// драйвер, как и здесь, отделяет параметры по первому ?
+	name, rawQuery, _ := strings.Cut(path, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", err
+	}
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", "busy_timeout(5000)")
+	return name + "?" + query.Encode(), nil
+}
@@ -0,0 +1,463 @@
+package usecase
+
+import (
+	"errors"
+	"tournament/internal/entity"
+)
+
+const (
+	SLOT_SEED = iota + 1
+	SLOT_WINNER
+	SLOT_LOSER
+)
+
+// bracketSlot - откуда приходит участник матча: посев или победитель/проигравший другого матча.
+type bracketSlot struct {
+	Kind  int
+	Seed  int
+	Match *bracketMatch
+}
+
+type bracketMatch struct {
+	GameType int
+	Round    int
+	Position int
+	Slots    [2]bracketSlot
+}
+
+// bracket - сетка плей-офф. Матчи создаются по мере того, как становятся известны их участники.
+type bracket struct {
+	Matches    []*bracketMatch
+	Final      *bracketMatch
+	ThirdPlace *bracketMatch
+	// LowerFinal - финал нижней сетки, проигравший в нем занимает третье место
+	LowerFinal *bracketMatch
+	// Tiers - матчи, проигравшие в которых выбывают, от финала к первому раунду
+	Tiers [][]*bracketMatch
+}
+
+type gameKey struct {
+	GameType int
+	Round    int
+	Position int
+}
+
+const (
+	outcomePending = iota
+	outcomeAbsent
+	outcomeDecided
+)
+
+type matchOutcome struct {
+	Status int
+	Winner int
+	Loser  int
+}
+
+// bracketResolver вычисляет состояние сетки по уже созданным матчам.
+type bracketResolver struct {
+	seeds    []entity.Team
+	games    map[gameKey]entity.Game
+	outcomes map[*bracketMatch]matchOutcome
+}
+
+func newBracketResolver(games []entity.Game, seeds []entity.Team) *bracketResolver {
+	index := map[gameKey]entity.Game{}
+	for _, game := range games {
+		index[gameKey{game.GameType, game.Round, game.Position}] = game
+	}
+	return &bracketResolver{
+		seeds:    seeds,
+		games:    index,
+		outcomes: map[*bracketMatch]matchOutcome{},
+	}
+}
+
+func (r *bracketResolver) game(m *bracketMatch) (entity.Game, bool) {
+	game, ok := r.games[gameKey{m.GameType, m.Round, m.Position}]
+	return game, ok
+}
+
+// slot возвращает команду слота; ok=false - участник еще не известен, id=0 - участника не будет.
+func (r *bracketResolver) slot(s bracketSlot) (int, bool) {
+	if s.Kind == SLOT_SEED {
+		if s.Seed < len(r.seeds) {
+			return r.seeds[s.Seed].ID, true
+		}
+		return 0, true
+	}
+
+	outcome := r.outcome(s.Match)
+	switch outcome.Status {
+	case outcomeAbsent:
+		return 0, true
+	case outcomeDecided:
+		if s.Kind == SLOT_WINNER {
+			return outcome.Winner, true
+		}
+		return outcome.Loser, true
+	}
+	return 0, false
+}
+
+// source - id матча, из которого приходит участник слота; 0 - посев или матч не проводился.
+func (r *bracketResolver) source(s bracketSlot) int {
+	if s.Kind == SLOT_SEED {
+		return 0
+	}
+	game, ok := r.game(s.Match)
+	if !ok {
+		return 0
+	}
+	return game.ID
+}
+
+func (r *bracketResolver) outcome(m *bracketMatch) matchOutcome {
+	if outcome, ok := r.outcomes[m]; ok {
+		return outcome
+	}
+
+	outcome := matchOutcome{Status: outcomePending}
+	if game, ok := r.game(m); ok {
+		if game.WinnerId != nil {
+			outcome = matchOutcome{Status: outcomeDecided, Winner: *game.WinnerId}
+			if !game.IsBye() {
+				outcome.Loser = game.Team1ID
+				if outcome.Winner == game.Team1ID {
+					outcome.Loser = game.Team2ID
+				}
+			}
+		}
+	} else {
+		team1, ok1 := r.slot(m.Slots[0])
+		team2, ok2 := r.slot(m.Slots[1])
+		// матч, в котором не будет ни одного участника, не проводится
+		if ok1 && ok2 && team1 == 0 && team2 == 0 {
+			outcome.Status = outcomeAbsent
+		}
+	}
+
+	r.outcomes[m] = outcome
+	return outcome
+}
+
+// nextStage возвращает все матчи сетки, участники которых уже известны, но которые еще не созданы.
+// Если соперника у команды не будет, матч создается сразу с победой команды (bye).
+func (b *bracket) nextStage(state FormatState, seeds []entity.Team) (*Stage, error) {
+	resolver := newBracketResolver(state.Games, seeds)
+
+	stage := &Stage{}
+	for _, m := range b.Matches {
+		if _, ok := resolver.game(m); ok {
+			continue
+		}
+		team1, ok1 := resolver.slot(m.Slots[0])
+		team2, ok2 := resolver.slot(m.Slots[1])
+		if !ok1 || !ok2 || (team1 == 0 && team2 == 0) {
+			continue
+		}
+
+		game := entity.Game{
+			Team1ID:           team1,
+			Team2ID:           team2,
+			GameType:          m.GameType,
+			Round:             m.Round,
+			Position:          m.Position,
+			Team1SourceGameID: resolver.source(m.Slots[0]),
+			Team2SourceGameID: resolver.source(m.Slots[1]),
+		}
+		if team1 == 0 {
+			game.Team1ID, game.Team2ID = team2, 0
+			game.Team1SourceGameID, game.Team2SourceGameID = game.Team2SourceGameID, 0
+		}
+		if game.IsBye() {
+			game.Team2SourceGameID = 0
+			winner := game.Team1ID
+			game.WinnerId = &winner
+		}
+		stage.Games = append(stage.Games, game)
+	}
+
+	if len(stage.Games) == 0 {
+		if resolver.outcome(b.Final).Status != outcomeDecided {
+			return nil, InvalidState(ERROR_CODE_GAMES_PENDING, "bracket is waiting for unfinished games")
+		}
+		return nil, nil
+	}
+	return stage, nil
+}
+
+func (b *bracket) winner(state FormatState, seeds []entity.Team) (*entity.Team, error) {
+	outcome := newBracketResolver(state.Games, seeds).outcome(b.Final)
+	if outcome.Status != outcomeDecided {
+		return nil, errors.New("final has not been played yet")
+	}
+	winner, err := state.Team(outcome.Winner)
+	if err != nil {
+		return nil, err
+	}
+	return &winner, nil
+}
+
+// placements - места по сетке: победитель и проигравший финала, участники матча за третье место,
+// затем выбывшие по ярусам. Возвращает и первое свободное после сетки место.
+func (b *bracket) placements(state FormatState, seeds []entity.Team) ([]entity.Placement, int, error) {
+	resolver := newBracketResolver(state.Games, seeds)
+	final := resolver.outcome(b.Final)
+	if final.Status != outcomeDecided {
+		return nil, 0, errors.New("final has not been played yet")
+	}
+
+	placements, next := exactPlacements([]int{final.Winner, final.Loser}, 1)
+	if b.ThirdPlace != nil {
+		thirdPlace := resolver.outcome(b.ThirdPlace)
+		if thirdPlace.Status == outcomeDecided {
+			var third []entity.Placement
+			third, next = exactPlacements([]int{thirdPlace.Winner, thirdPlace.Loser}, next)
+			placements = append(placements, third...)
+		}
+	}
+
+	eliminated, next := b.eliminatedPlacements(resolver, next)
+	return append(placements, eliminated...), next, nil
+}
+
+// eliminatedPlacements - проигравшие в матчах одного яруса делят места между собой.
+func (b *bracket) eliminatedPlacements(resolver *bracketResolver, next int) ([]entity.Placement, int) {
+	var placements []entity.Placement
+	for _, tier := range b.Tiers {
+		var losers []int
+		for _, m := range tier {
+			outcome := resolver.outcome(m)
+			if outcome.Status == outcomeDecided && outcome.Loser != 0 {
+				losers = append(losers, outcome.Loser)
+			}
+		}
+		placements = append(placements, sharedPlacements(losers, next)...)
+		next += len(losers)
+	}
+	return placements, next
+}
+
+// exactPlacements раздает командам места по порядку начиная с next, пропуская отсутствующих.
+func exactPlacements(ids []int, next int) ([]entity.Placement, int) {
+	var placements []entity.Placement
+	for _, id := range ids {
+		if id == 0 {
+			continue
+		}
+		placements = append(placements, entity.Placement{TeamID: id, PlaceFrom: next, PlaceTo: next})
+		next++
+	}
+	return placements, next
+}
+
+func sharedPlacements(ids []int, next int) []entity.Placement {
+	var placements []entity.Placement
+	for _, id := range ids {
+		placements = append(placements, entity.Placement{TeamID: id, PlaceFrom: next, PlaceTo: next + len(ids) - 1})
+	}
+	return placements
+}
+
+// newSingleEliminationBracket строит сетку на выбывание, дополняя число участников
+// до степени двойки. Пропуски (bye) достаются лучшим по посеву командам.
+// С thirdPlace проигравшие в полуфиналах играют матч за третье место.
+func newSingleEliminationBracket(teamsCount int, thirdPlace bool) (*bracket, error) {
+	if teamsCount < 2 {
+		return nil, InvalidState(ERROR_CODE_NOT_ENOUGH_TEAMS, "at least 2 teams are required for playoff, got %d", teamsCount)
+	}
+
+	b := &bracket{}
+	rounds := eliminationRounds(teamsCount, func(round int, rounds int) int {
+		switch round {
+		case rounds:
+			return entity.GAME_TYPE_PLAYOFF_FINAL
+		case rounds - 1:
+			return entity.GAME_TYPE_PLAYOFF_SEMIFINAL
+		}
+		return entity.GAME_TYPE_PLAYOFF_STAGE_1
+	})
+	for _, round := range rounds {
+		b.Matches = append(b.Matches, round...)
+	}
+	b.Final = rounds[len(rounds)-1][0]
+
+	for round := len(rounds) - 2; round >= 0; round-- {
+		// проигравшие в полуфиналах еще играют матч за третье место
+		if round == len(rounds)-2 && thirdPlace {
+			continue
+		}
+		b.Tiers = append(b.Tiers, rounds[round])
+	}
+
+	if thirdPlace && len(rounds) > 1 {
+		semifinals := rounds[len(rounds)-2]
+		b.ThirdPlace = &bracketMatch{
+			GameType: entity.GAME_TYPE_PLAYOFF_THIRD_PLACE,
+			Round:    b.Final.Round,
+			Slots: [2]bracketSlot{
+				{Kind: SLOT_LOSER, Match: semifinals[0]},
+				{Kind: SLOT_LOSER, Match: semifinals[1]},
+			},
+		}
+		b.Matches = append(b.Matches, b.ThirdPlace)
+	}
+
+	return b, nil
+}
+
+// newDoubleEliminationBracket строит сетку с верхней и нижней частью и гранд-финалом.
+// Проигравшие первого раунда верхней сетки играют между собой в первом раунде нижней,
+// проигравшие каждого следующего раунда попадают в нижнюю сетку к победителям ее предыдущего раунда.
+func newDoubleEliminationBracket(teamsCount int) (*bracket, error) {
+	if teamsCount < 2 {
+		return nil, InvalidState(ERROR_CODE_NOT_ENOUGH_TEAMS, "at least 2 teams are required for playoff, got %d", teamsCount)
+	}
+
+	b := &bracket{}
+	upper := eliminationRounds(teamsCount, func(int, int) int {
+		return entity.GAME_TYPE_WINNERS_BRACKET
+	})
+	for _, round := range upper {
+		b.Matches = append(b.Matches, round...)
+	}
+	upperFinal := upper[len(upper)-1][0]
+
+	// при двух командах нижней сетки нет: в гранд-финал выходит проигравший финала верхней
+	lowerChampion := bracketSlot{Kind: SLOT_LOSER, Match: upperFinal}
+	if len(upper) > 1 {
+		lowerRound := 1
+		var lower []*bracketMatch
+		for position := 0; position < len(upper[0])/2; position++ {
+			lower = append(lower, &bracketMatch{
+				GameType: entity.GAME_TYPE_LOSERS_BRACKET,
+				Round:    lowerRound,
+				Position: position,
+				Slots: [2]bracketSlot{
+					{Kind: SLOT_LOSER, Match: upper[0][2*position]},
+					{Kind: SLOT_LOSER, Match: upper[0][2*position+1]},
+				},
+			})
+		}
+		b.Matches = append(b.Matches, lower...)
+		// в двойной сетке выбывают только проигравшие в нижней сетке
+		lowerRounds := [][]*bracketMatch{lower}
+
+		for round := 1; round < len(upper); round++ {
+			// раунд, в который приходят проигравшие верхней сетки;
+			// через раунд порядок разворачивается, чтобы не было повторных встреч
+			dropped := upper[round]
+			lowerRound++
+			var current []*bracketMatch
+			for position := range lower {
+				source := position
+				if round%2 == 1 {
+					source = len(dropped) - 1 - position
+				}
+				current = append(current, &bracketMatch{
+					GameType: entity.GAME_TYPE_LOSERS_BRACKET,
+					Round:    lowerRound,
+					Position: position,
+					Slots: [2]bracketSlot{
+						{Kind: SLOT_WINNER, Match: lower[position]},
+						{Kind: SLOT_LOSER, Match: dropped[source]},
+					},
+				})
+			}
+			b.Matches = append(b.Matches, current...)
+			lowerRounds = append(lowerRounds, current)
+			lower = current
+
+			if len(lower) == 1 {
+				break
+			}
+
+			// победители нижней сетки играют между собой
+			lowerRound++
+			current = nil
+			for position := 0; position < len(lower)/2; position++ {
+				current = append(current, &bracketMatch{
+					GameType: entity.GAME_TYPE_LOSERS_BRACKET,
+					Round:    lowerRound,
+					Position: position,
+					Slots: [2]bracketSlot{
+						{Kind: SLOT_WINNER, Match: lower[2*position]},
+						{Kind: SLOT_WINNER, Match: lower[2*position+1]},
+					},
+				})
+			}
+			b.Matches = append(b.Matches, current...)
+			lowerRounds = append(lowerRounds, current)
+			lower = current
+		}
+		b.LowerFinal = lower[0]
+		for round := len(lowerRounds) - 1; round >= 0; round-- {
+			b.Tiers = append(b.Tiers, lowerRounds[round])
+		}
+		lowerChampion = bracketSlot{Kind: SLOT_WINNER, Match: b.LowerFinal}
+	}
+
+	b.Final = &bracketMatch{
+		GameType: entity.GAME_TYPE_GRAND_FINAL,
+		Round:    1,
+		Slots: [2]bracketSlot{
+			{Kind: SLOT_WINNER, Match: upperFinal},
+			lowerChampion,
+		},
+	}
+	b.Matches = append(b.Matches, b.Final)
+
+	return b, nil
+}
+
+// eliminationRounds строит раунды сетки на выбывание; gameType определяет тип матчей раунда.
+func eliminationRounds(teamsCount int, gameType func(round int, rounds int) int) [][]*bracketMatch {
+	size := bracketSize(teamsCount)
+	rounds := 0
+	for n := size; n > 1; n /= 2 {
+		rounds++
+	}
+
+	var result [][]*bracketMatch
+	order := seedingOrder(size)
+	for round := 1; round <= rounds; round++ {
+		var current []*bracketMatch
+		for position := 0; position < size>>round; position++ {
+			m := &bracketMatch{GameType: gameType(round, rounds), Round: round, Position: position}
+			if round == 1 {
+				m.Slots[0] = bracketSlot{Kind: SLOT_SEED, Seed: order[2*position]}
+				m.Slots[1] = bracketSlot{Kind: SLOT_SEED, Seed: order[2*position+1]}
+			} else {
+				previous := result[round-2]
+				m.Slots[0] = bracketSlot{Kind: SLOT_WINNER, Match: previous[2*position]}
+				m.Slots[1] = bracketSlot{Kind: SLOT_WINNER, Match: previous[2*position+1]}
+			}
+			current = append(current, m)
+		}
+		result = append(result, current)
+	}
+	return result
+}
+
+func bracketSize(teamsCount int) int {
+	size := 1
+	for size < teamsCount {
+		size *= 2
+	}
+	return size
+}
+
+// seedingOrder - классическая расстановка посева (с нуля): 1-8, 4-5, 2-7, 3-6 для восьми команд,
+// так что сильнейшие встречаются как можно позже.
+func seedingOrder(size int) []int {
+	order := []int{0}
+	for n := 1; n < size; n *= 2 {
+		next := make([]int, 0, 2*n)
+		for _, seed := range order {
+			next = append(next, seed, 2*n-1-seed)
+		}
+		order = next
+	}
+	return order
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"slices"
+	"testing"
+	"tournament/internal/entity"
+)
+
+func TestSeedingOrder(t *testing.T) {
+	want := []int{0, 7, 3, 4, 1, 6, 2, 5}
+	if got := seedingOrder(8); !slices.Equal(got, want) {
+		t.Errorf("seeding order %v, want %v", got, want)
+	}
+}
+
+func TestSingleEliminationByes(t *testing.T) {
+	for _, teams := range []int{2, 3, 5, 6, 8, 13} {
+		state := FormatState{Teams: newTeams(teams)}
+		stage, err := SingleEliminationFormat{}.NextStage(state)
+		if err != nil {
+			t.Fatalf("%d teams: first stage: %v", teams, err)
+		}
+
+		// пропуски получают лучшие по посеву, остальные играют первый раунд
+		byes := bracketSize(teams) - teams
+		played := map[int]bool{}
+		for _, game := range stage.Games {
+			if game.Round != 1 {
+				t.Errorf("%d teams: game of round %d in the first stage", teams, game.Round)
+			}
+			if game.IsBye() {
+				if game.Team1ID > byes || game.WinnerId == nil || *game.WinnerId != game.Team1ID {
+					t.Errorf("%d teams: bye of team %d with winner %v", teams, game.Team1ID, game.WinnerId)
+				}
+			} else if game.Team1ID <= byes || game.Team2ID <= byes {
+				t.Errorf("%d teams: team with a bye plays %d vs %d", teams, game.Team1ID, game.Team2ID)
+			}
+			played[game.Team1ID] = true
+			played[game.Team2ID] = true
+		}
+		for _, team := range state.Teams {
+			if !played[team.ID] {
+				t.Errorf("%d teams: team %d is missing from the first round", teams, team.ID)
+			}
+		}
+
+		// при победах сильнейших в финале встречаются первый и второй посев
+		state = playFormat(t, SingleEliminationFormat{}, state, higherSeedWins)
+		real := 0
+		for _, game := range state.Games {
+			if !game.IsBye() {
+				real++
+			}
+		}
+		if real != teams-1 {
+			t.Errorf("%d teams: %d games played, want %d", teams, real, teams-1)
+		}
+		final := state.GamesByType(entity.GAME_TYPE_PLAYOFF_FINAL)
+		if len(final) != 1 || min(final[0].Team1ID, final[0].Team2ID) != 1 || max(final[0].Team1ID, final[0].Team2ID) != 2 {
+			t.Errorf("%d teams: final %+v, want seeds 1 and 2", teams, final)
+		}
+		winner, err := SingleEliminationFormat{}.Winner(state)
+		if err != nil || winner.ID != 1 {
+			t.Errorf("%d teams: winner %v, %v, want team 1", teams, winner, err)
+		}
+	}
+}
+
+func findGame(t *testing.T, games []entity.Game, gameType int, round int) entity.Game {
+	t.Helper()
+	for _, game := range games {
+		if game.GameType == gameType && game.Round == round {
+			return game
+		}
+	}
+	t.Fatalf("no game of type %d and round %d among %+v", gameType, round, games)
+	return entity.Game{}
+}
+
+func checkTeams(t *testing.T, game entity.Game, team1 int, team2 int) {
+	t.Helper()
+	if !(game.Team1ID == team1 && game.Team2ID == team2) && !(game.Team1ID == team2 && game.Team2ID == team1) {
+		t.Errorf("game of type %d and round %d is %d vs %d, want %d vs %d", game.GameType, game.Round, game.Team1ID, game.Team2ID, team1, team2)
+	}
+}
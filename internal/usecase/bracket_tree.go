@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"net/http"
+	"sort"
+	"tournament/internal/entity"
+)
+
+const (
+	BRACKET_SOURCE_SEED   = "seed"
+	BRACKET_SOURCE_WINNER = "winner"
+	BRACKET_SOURCE_LOSER  = "loser"
+)
+
+type BracketResponse struct {
+	StatusCode int                `json:"status_code"`
+	Tournament *entity.Tournament `json:"tournament"`
+	Rounds     []BracketRound     `json:"rounds"`
+}
+
+// BracketRound - матчи одного раунда одной сетки, Stage - название из GameTypeNames.
+type BracketRound struct {
+	Stage   string         `json:"stage"`
+	Round   int            `json:"round"`
+	Matches []BracketMatch `json:"matches"`
+}
+
+type BracketMatch struct {
+	GameID   int              `json:"game_id"`
+	Position int              `json:"position"`
+	Status   string           `json:"status"`
+	BestOf   int              `json:"best_of"`
+	Slots    [2]BracketSlot   `json:"slots"`
+	WinnerID *int             `json:"winner_id"`
+	Maps     []entity.GameMap `json:"maps"`
+}
+
+// BracketSlot - участник матча и матч, из которого он пришел. Team == nil - соперника нет (bye).
+type BracketSlot struct {
+	Slot         int          `json:"slot"`
+	Team         *entity.Team `json:"team"`
+	Score        *int         `json:"score"`
+	Source       string       `json:"source"`
+	SourceGameID *int         `json:"source_game_id"`
+}
+
+// GetBracket возвращает плей-офф турнира деревом: раунды в порядке проведения,
+// у каждого участника - матч, победителем или проигравшим которого он стал.
+func (t *TournamentUseCase) GetBracket(tournamentID int) (*BracketResponse, error) {
+	tournament, err := t.getTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := t.TournamentRepository.GetTeams(tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	games, err := t.GameRepository.GetByTournament(tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BracketResponse{
+		StatusCode: http.StatusOK,
+		Tournament: tournament,
+		Rounds:     bracketRounds(teams, games),
+	}, nil
+}
+
+func bracketRounds(teams []entity.Team, games []entity.Game) []BracketRound {
+	teamsByID := map[int]entity.Team{}
+	for _, team := range teams {
+		teamsByID[team.ID] = team
+	}
+	gamesByID := map[int]entity.Game{}
+	for _, game := range games {
+		gamesByID[game.ID] = game
+	}
+
+	type roundKey struct {
+		GameType int
+		Round    int
+	}
+	stages := map[roundKey]int{}
+	matches := map[roundKey][]entity.Game{}
+	for _, game := range games {
+		if game.IsGroupStage() {
+			continue
+		}
+		key := roundKey{game.GameType, game.Round}
+		if stage, ok := stages[key]; !ok || game.Stage < stage {
+			stages[key] = game.Stage
+		}
+		matches[key] = append(matches[key], game)
+	}
+
+	keys := make([]roundKey, 0, len(matches))
+	for key := range matches {
+		keys = append(keys, key)
+	}
+	// раунды идут в порядке стадий, в одной стадии верхняя сетка раньше нижней
+	sort.Slice(keys, func(i, j int) bool {
+		if stages[keys[i]] != stages[keys[j]] {
+			return stages[keys[i]] < stages[keys[j]]
+		}
+		if keys[i].GameType != keys[j].GameType {
+			return keys[i].GameType < keys[j].GameType
+		}
+		return keys[i].Round < keys[j].Round
+	})
+
+	rounds := []BracketRound{}
+	for _, key := range keys {
+		round := BracketRound{Stage: entity.GameTypeNames[key.GameType], Round: key.Round}
+		sort.Slice(matches[key], func(i, j int) bool {
+			return matches[key][i].Position < matches[key][j].Position
+		})
+		for _, game := range matches[key] {
+			round.Matches = append(round.Matches, BracketMatch{
+				GameID:   game.ID,
+				Position: game.Position,
+				Status:   entity.GameStatusNames[game.Status],
+				BestOf:   game.BestOf,
+				Slots: [2]BracketSlot{
+					bracketSlotOf(1, game.Team1ID, game.Team1Score, game.Team1SourceGameID, teamsByID, gamesByID),
+					bracketSlotOf(2, game.Team2ID, game.Team2Score, game.Team2SourceGameID, teamsByID, gamesByID),
+				},
+				WinnerID: game.WinnerId,
+				Maps:     game.Maps,
+			})
+		}
+		rounds = append(rounds, round)
+	}
+	return rounds
+}
+
+func bracketSlotOf(slot int, teamID int, score *int, sourceID int, teams map[int]entity.Team, games map[int]entity.Game) BracketSlot {
+	result := BracketSlot{Slot: slot, Score: score}
+	if teamID == 0 {
+		return result
+	}
+	team := teams[teamID]
+	result.Team = &team
+
+	source, ok := games[sourceID]
+	if !ok {
+		result.Source = BRACKET_SOURCE_SEED
+		return result
+	}
+	result.SourceGameID = &source.ID
+	result.Source = BRACKET_SOURCE_LOSER
+	if source.WinnerId != nil && *source.WinnerId == teamID {
+		result.Source = BRACKET_SOURCE_WINNER
+	}
+	return result
+}
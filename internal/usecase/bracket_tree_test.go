@@ -0,0 +1,92 @@
+package usecase_test
+
+import (
+	"testing"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+)
+
+func TestGetBracket(t *testing.T) {
+	uc := newUseCase()
+	id, teams := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 3)
+	started, err := uc.StartTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+	var semifinal entity.Game
+	for _, game := range started.Games {
+		if !game.IsBye() {
+			semifinal = game
+		}
+	}
+	reportResult(t, uc, id, semifinal, 0, 1)
+
+	res, err := uc.GetBracket(id)
+	if err != nil {
+		t.Fatalf("bracket: %v", err)
+	}
+	if len(res.Rounds) != 2 || res.Rounds[0].Stage != "semifinal" || res.Rounds[1].Stage != "final" {
+		t.Fatalf("rounds %+v, want semifinal and final", res.Rounds)
+	}
+
+	// первый посев проходит в финал без игры, у пропуска нет соперника
+	bye := res.Rounds[0].Matches[0]
+	if bye.Slots[0].Team == nil || bye.Slots[0].Team.ID != teams[0].ID || bye.Slots[0].Source != usecase.BRACKET_SOURCE_SEED || bye.Slots[1].Team != nil {
+		t.Errorf("bye %+v", bye)
+	}
+	played := res.Rounds[0].Matches[1]
+	if played.Status != "finished" || played.WinnerID == nil || *played.WinnerID != semifinal.Team2ID {
+		t.Errorf("semifinal %+v", played)
+	}
+
+	final := res.Rounds[1].Matches[0]
+	if final.Status != "scheduled" {
+		t.Errorf("final status %s", final.Status)
+	}
+	for i, want := range []struct {
+		team   int
+		source int
+	}{{teams[0].ID, bye.GameID}, {semifinal.Team2ID, semifinal.ID}} {
+		slot := final.Slots[i]
+		if slot.Team == nil || slot.Team.ID != want.team || slot.Source != usecase.BRACKET_SOURCE_WINNER || slot.SourceGameID == nil || *slot.SourceGameID != want.source {
+			t.Errorf("final slot %d %+v, want winner %d of game %d", slot.Slot, slot, want.team, want.source)
+		}
+	}
+}
+
+func TestGetBracketShowsLosersSource(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "double_elimination", entity.TournamentSettings{}, 4)
+	started, err := uc.StartTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+	for _, game := range started.Games {
+		reportResult(t, uc, id, game, 1, 0)
+	}
+
+	res, err := uc.GetBracket(id)
+	if err != nil {
+		t.Fatalf("bracket: %v", err)
+	}
+	var lower *usecase.BracketRound
+	for i, round := range res.Rounds {
+		if round.Stage == "losers_bracket" {
+			lower = &res.Rounds[i]
+		}
+	}
+	if lower == nil || len(lower.Matches) != 1 {
+		t.Fatalf("rounds %+v, want one lower bracket match", res.Rounds)
+	}
+	for _, slot := range lower.Matches[0].Slots {
+		if slot.Source != usecase.BRACKET_SOURCE_LOSER || slot.SourceGameID == nil {
+			t.Errorf("lower bracket slot %+v, want loser of an upper bracket game", slot)
+		}
+	}
+	// групповых матчей в сетке нет
+	for _, round := range res.Rounds {
+		if round.Stage == "group" || round.Stage == "swiss" {
+			t.Errorf("group stage round %+v in bracket", round)
+		}
+	}
+}
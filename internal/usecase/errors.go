@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// виды ошибок предметной области, handler переводит их в HTTP-статусы
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrInvalidState = errors.New("invalid state")
+	ErrValidation   = errors.New("validation failed")
+)
+
+// коды ошибок - стабильная часть ответа, по которой клиенты различают ошибки
+const (
+	ERROR_CODE_TOURNAMENT_NOT_FOUND = "tournament_not_found"
+	ERROR_CODE_TEAM_NOT_FOUND       = "team_not_found"
+	ERROR_CODE_GAME_NOT_FOUND       = "game_not_found"
+	ERROR_CODE_STAGE_NOT_FOUND      = "stage_not_found"
+
+	ERROR_CODE_RESULT_ALREADY_REPORTED = "result_already_reported"
+	ERROR_CODE_SEED_ALREADY_SET        = "seed_already_set"
+	ERROR_CODE_TEAM_SEED_TAKEN         = "team_seed_taken"
+	ERROR_CODE_TEAM_NAME_TAKEN         = "team_name_taken"
+	ERROR_CODE_TOURNAMENT_FULL         = "tournament_full"
+
+	ERROR_CODE_INVALID_STATUS_TRANSITION = "invalid_status_transition"
+	ERROR_CODE_REGISTRATION_CLOSED       = "registration_closed"
+	ERROR_CODE_TOURNAMENT_STARTED        = "tournament_started"
+	ERROR_CODE_TOURNAMENT_FINISHED       = "tournament_finished"
+	ERROR_CODE_TOURNAMENT_CANCELLED      = "tournament_cancelled"
+	ERROR_CODE_TOURNAMENT_NOT_FINISHED   = "tournament_not_finished"
+	ERROR_CODE_RESULTS_NOT_ACCEPTED      = "results_not_accepted"
+	ERROR_CODE_GAMES_PENDING             = "games_pending"
+	ERROR_CODE_NOT_ENOUGH_TEAMS          = "not_enough_teams"
+	ERROR_CODE_TOO_MANY_TEAMS            = "too_many_teams"
+	ERROR_CODE_BYE_GAME                  = "bye_game"
+
+	ERROR_CODE_UNKNOWN_FORMAT      = "unknown_format"
+	ERROR_CODE_INVALID_SETTINGS    = "invalid_settings"
+	ERROR_CODE_INVALID_SORT        = "invalid_sort"
+	ERROR_CODE_UNKNOWN_STAGE       = "unknown_stage"
+	ERROR_CODE_UNKNOWN_GAME_STATUS = "unknown_game_status"
+	ERROR_CODE_INVALID_SCORE       = "invalid_score"
+	ERROR_CODE_INVALID_WINNER      = "invalid_winner"
+	ERROR_CODE_DRAW_NOT_ALLOWED    = "draw_not_allowed"
+	ERROR_CODE_INVALID_TEAM_NAME   = "invalid_team_name"
+	ERROR_CODE_INVALID_IMPORT      = "invalid_import"
+)
+
+// Error - ошибка предметной области: Kind - один из Err*, Code - один из ERROR_CODE_*.
+// Format с Args - сообщение на английском; Format служит ключом для перевода сообщения.
+// Fields - ошибки отдельных полей или строк запроса по их именам.
+// errors.Is(err, ErrNotFound) и подобные проверки работают через Unwrap.
+type Error struct {
+	Kind   error
+	Code   string
+	Format string
+	Args   []any
+	Fields map[string]error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf(e.Format, e.Args...)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func NotFound(code string, format string, args ...any) error {
+	return &Error{Kind: ErrNotFound, Code: code, Format: format, Args: args}
+}
+
+func Conflict(code string, format string, args ...any) error {
+	return &Error{Kind: ErrConflict, Code: code, Format: format, Args: args}
+}
+
+func InvalidState(code string, format string, args ...any) error {
+	return &Error{Kind: ErrInvalidState, Code: code, Format: format, Args: args}
+}
+
+func Validation(code string, format string, args ...any) error {
+	return &Error{Kind: ErrValidation, Code: code, Format: format, Args: args}
+}
+
+// notFound заменяет sql.ErrNoRows, которой репозитории сообщают об отсутствии записи, на ошибку NotFound.
+func notFound(err error, code string, format string, args ...any) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return NotFound(code, format, args...)
+	}
+	return err
+}
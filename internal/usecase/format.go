@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"tournament/internal/entity"
+)
+
+const DEFAULT_FORMAT = "classic"
+
+// MAX_TEAMS - предел количества команд в турнире любого формата. Сетка на выбывание дополняется
+// пропусками до степени двойки, поэтому 128 команд - это 7 раундов и 127 матчей в одной сетке
+// (около 255 в двойной) и не больше 8128 матчей в одной группе.
+const MAX_TEAMS = 128
+
+// Format описывает формат проведения турнира: по уже сыгранным матчам
+// формат решает, какие пары играют на следующей стадии и кто победил.
+type Format interface {
+	Name() string
+	ValidateSettings(settings entity.TournamentSettings) error
+	// MaxTeams - сколько команд можно зарегистрировать в турнире с этими настройками.
+	MaxTeams(settings entity.TournamentSettings) int
+	// NextStage возвращает матчи следующей стадии или nil, если турнир завершен.
+	// Вызывается только когда все матчи предыдущих стадий сыграны.
+	NextStage(state FormatState) (*Stage, error)
+	Winner(state FormatState) (*entity.Team, error)
+	// Placements - итоговые места всех команд по сыгранным стадиям, начиная с победителя.
+	Placements(state FormatState) ([]entity.Placement, error)
+}
+
+// FormatState - все, что известно о турнире на момент генерации стадии.
+type FormatState struct {
+	Tournament entity.Tournament
+	Teams      []entity.Team
+	Games      []entity.Game
+	Lots       []entity.Lot
+}
+
+// Stage - набор матчей одной стадии, которые нужно создать, и жребий, вытянутый для нее.
+type Stage struct {
+	Games []entity.Game
+	Lots  []entity.Lot
+}
+
+var formats = map[string]Format{}
+
+// RegisterFormat делает формат доступным для создания турниров по его имени.
+func RegisterFormat(format Format) {
+	formats[format.Name()] = format
+}
+
+func GetFormat(name string) (Format, error) {
+	format, ok := formats[name]
+	if !ok {
+		return nil, Validation(ERROR_CODE_UNKNOWN_FORMAT, "unknown tournament format %q", name)
+	}
+	return format, nil
+}
+
+// validateSettings проверяет общие для всех форматов настройки, затем настройки формата.
+func validateSettings(format Format, settings entity.TournamentSettings) error {
+	for stage, bestOf := range settings.BestOf {
+		known := false
+		for _, name := range entity.GameTypeNames {
+			known = known || name == stage
+		}
+		if !known {
+			return Validation(ERROR_CODE_INVALID_SETTINGS, "unknown stage %q in best_of", stage)
+		}
+		if bestOf != 1 && bestOf != 3 && bestOf != 5 && bestOf != 7 {
+			return Validation(ERROR_CODE_INVALID_SETTINGS, "best_of for %s must be 1, 3, 5 or 7", stage)
+		}
+	}
+	return format.ValidateSettings(settings)
+}
+
+func FormatNames() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s FormatState) GamesByType(gameType int) []entity.Game {
+	var games []entity.Game
+	for _, game := range s.Games {
+		if game.GameType == gameType {
+			games = append(games, game)
+		}
+	}
+	return games
+}
+
+func (s FormatState) Team(id int) (entity.Team, error) {
+	for _, team := range s.Teams {
+		if team.ID == id {
+			return team, nil
+		}
+	}
+	return entity.Team{}, fmt.Errorf("team %d not found in tournament", id)
+}
+
+func newGame(gameType int, team1 entity.Team, team2 entity.Team) entity.Game {
+	return entity.Game{
+		Team1ID:  team1.ID,
+		Team2ID:  team2.ID,
+		GameType: gameType,
+	}
+}
@@ -0,0 +1,335 @@
+package usecase
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"tournament/internal/entity"
+)
+
+const DEFAULT_GROUPS = 2
+const MAX_GROUPS = 26
+const DEFAULT_ADVANCE_PER_GROUP = 4
+
+// ClassicFormat - групповой этап (каждый с каждым), затем плей-офф из лучших команд каждой группы.
+// Места в группах определяются по очкам и критериям из настроек (CalculateStandings).
+// По умолчанию две группы, из каждой выходят четыре команды, лучшие играют с худшими из другой группы.
+type ClassicFormat struct{}
+
+func init() {
+	RegisterFormat(ClassicFormat{})
+}
+
+func (f ClassicFormat) Name() string {
+	return "classic"
+}
+
+func (f ClassicFormat) ValidateSettings(settings entity.TournamentSettings) error {
+	if settings.Groups < 0 || settings.Groups > MAX_GROUPS {
+		return Validation(ERROR_CODE_INVALID_SETTINGS, "groups must be between 1 and 26")
+	}
+	if settings.GroupSize < 0 || settings.GroupSize == 1 {
+		return Validation(ERROR_CODE_INVALID_SETTINGS, "group_size must be at least 2")
+	}
+	if settings.AdvancePerGroup < 0 {
+		return Validation(ERROR_CODE_INVALID_SETTINGS, "advance_per_group must be positive")
+	}
+	if err := ValidateTiebreakers(settings.Tiebreakers); err != nil {
+		return err
+	}
+
+	groups := settings.Groups
+	if groups == 0 && settings.GroupSize == 0 {
+		groups = DEFAULT_GROUPS
+	}
+	// по одному group_size количество групп станет известно только после регистрации
+	if groups == 0 && len(settings.Crossover) > 0 {
+		return Validation(ERROR_CODE_INVALID_SETTINGS, "crossover requires groups to be set")
+	}
+	_, err := parseCrossover(settings.Crossover, groups, advancePerGroup(settings))
+	return err
+}
+
+// MaxTeams ограничен только размером групп: без GroupSize группы могут быть любого размера.
+func (f ClassicFormat) MaxTeams(settings entity.TournamentSettings) int {
+	if settings.GroupSize == 0 {
+		return MAX_TEAMS
+	}
+	groups := settings.Groups
+	if groups == 0 {
+		groups = MAX_GROUPS
+	}
+	return min(groups*settings.GroupSize, MAX_TEAMS)
+}
+
+func (f ClassicFormat) NextStage(state FormatState) (*Stage, error) {
+	if len(state.GamesByType(entity.GAME_TYPE_GROUP)) == 0 {
+		return f.groupStage(state)
+	}
+
+	playoff, seeds, err := f.playoff(state)
+	if err != nil {
+		return nil, err
+	}
+	return playoff.nextStage(state, seeds)
+}
+
+func (f ClassicFormat) Winner(state FormatState) (*entity.Team, error) {
+	playoff, seeds, err := f.playoff(state)
+	if err != nil {
+		return nil, err
+	}
+	return playoff.winner(state, seeds)
+}
+
+// Placements - места по плей-офф, за ними все команды, не попавшие в плей-офф, по местам в группах:
+// команды, занявшие одинаковое место в своих группах, делят места между собой.
+func (f ClassicFormat) Placements(state FormatState) ([]entity.Placement, error) {
+	playoff, seeds, err := f.playoff(state)
+	if err != nil {
+		return nil, err
+	}
+	placements, next, err := playoff.placements(state, seeds)
+	if err != nil {
+		return nil, err
+	}
+
+	inPlayoff := map[int]bool{}
+	for _, team := range seeds {
+		inPlayoff[team.ID] = true
+	}
+	groups, err := f.groupStandings(state)
+	if err != nil {
+		return nil, err
+	}
+	for rank, ranked := 0, true; ranked; rank++ {
+		ranked = false
+		var ids []int
+		for _, standings := range groups {
+			if rank >= len(standings) {
+				continue
+			}
+			ranked = true
+			if team := standings[rank].Team; !inPlayoff[team.ID] {
+				ids = append(ids, team.ID)
+			}
+		}
+		placements = append(placements, sharedPlacements(ids, next)...)
+		next += len(ids)
+	}
+	return placements, nil
+}
+
+func (f ClassicFormat) playoff(state FormatState) (*bracket, []entity.Team, error) {
+	seeds, err := f.playoffSeeds(state)
+	if err != nil {
+		return nil, nil, err
+	}
+	playoff, err := newSingleEliminationBracket(len(seeds), state.Tournament.Settings.ThirdPlaceMatch)
+	if err != nil {
+		return nil, nil, err
+	}
+	return playoff, seeds, nil
+}
+
+func (f ClassicFormat) groupStage(state FormatState) (*Stage, error) {
+	rng := state.Rand(RAND_GROUPS)
+
+	//разделение на группы
+	groups, err := splitToGroups(rng, state.Teams, state.Tournament.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	stage := &Stage{}
+	//жребий для равенства в таблице тянется сразу и сохраняется вместе с расписанием
+	for i, value := range rng.Perm(len(state.Teams)) {
+		stage.Lots = append(stage.Lots, entity.Lot{TeamID: state.Teams[i].ID, Value: value + 1})
+	}
+
+	//генерация расписания для каждой группы
+	for i, group := range groups {
+		games := roundRobin(group, entity.GAME_TYPE_GROUP)
+		for j := range games {
+			games[j].Group = i + 1
+		}
+		stage.Games = append(stage.Games, games...)
+	}
+
+	return stage, nil
+}
+
+// playoffSeeds - посев плей-офф. По умолчанию сначала первые места групп, затем вторые и т.д.,
+// так что лучшие играют с худшими с другой группы (A1-B4, B1-A4, A2-B3, B2-A3).
+// Если в настройках задан crossover, пары первого раунда берутся из него.
+func (f ClassicFormat) playoffSeeds(state FormatState) ([]entity.Team, error) {
+	settings := state.Tournament.Settings
+	advance := advancePerGroup(settings)
+
+	standings, err := f.groupStandings(state)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups [][]entity.Team
+	for _, group := range standings {
+		var winners []entity.Team
+		for i := 0; i < len(group) && i < advance; i++ {
+			winners = append(winners, group[i].Team)
+		}
+		groups = append(groups, winners)
+	}
+
+	crossover, err := parseCrossover(settings.Crossover, len(groups), advance)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(crossover) == 0 {
+		var seeds []entity.Team
+		for rank := 0; rank < advance; rank++ {
+			for _, winners := range groups {
+				if rank < len(winners) {
+					seeds = append(seeds, winners[rank])
+				}
+			}
+		}
+		return seeds, nil
+	}
+
+	// раскладываем пары по позициям стандартной сетки, чтобы они встретились в первом раунде
+	seeds := make([]entity.Team, len(crossover))
+	order := seedingOrder(len(crossover))
+	for slot, place := range crossover {
+		if place.Rank >= len(groups[place.Group]) {
+			return nil, InvalidState(ERROR_CODE_NOT_ENOUGH_TEAMS, "group %s has no team at place %d", groupName(place.Group), place.Rank+1)
+		}
+		seeds[order[slot]] = groups[place.Group][place.Rank]
+	}
+	return seeds, nil
+}
+
+// groupStandings - таблицы групп по порядку групп.
+func (f ClassicFormat) groupStandings(state FormatState) ([][]Standing, error) {
+	var groups [][]Standing
+	for group := 1; ; group++ {
+		games := gamesByGroup(state.GamesByType(entity.GAME_TYPE_GROUP), group)
+		if len(games) == 0 {
+			return groups, nil
+		}
+		standings, err := CalculateStandings(state, games, state.Tournament.Settings.Tiebreakers)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, standings)
+	}
+}
+
+type groupPlace struct {
+	Group int
+	Rank  int
+}
+
+// parseCrossover разбирает пары вида "A1-B4" в места групп по порядку слотов сетки.
+// Пары должны покрывать все места, выходящие из групп, иначе часть вышедших команд не попадет в плей-офф.
+func parseCrossover(crossover []string, groups int, advance int) ([]groupPlace, error) {
+	if len(crossover) == 0 {
+		return nil, nil
+	}
+	if len(crossover)&(len(crossover)-1) != 0 {
+		return nil, Validation(ERROR_CODE_INVALID_SETTINGS, "crossover must contain a power of two pairs")
+	}
+
+	var places []groupPlace
+	used := map[groupPlace]bool{}
+	for _, pair := range crossover {
+		labels := strings.Split(pair, "-")
+		if len(labels) != 2 {
+			return nil, Validation(ERROR_CODE_INVALID_SETTINGS, "invalid crossover pair %q, expected format like A1-B4", pair)
+		}
+		for _, label := range labels {
+			label = strings.ToUpper(strings.TrimSpace(label))
+			if len(label) < 2 || label[0] < 'A' || label[0] > 'Z' {
+				return nil, Validation(ERROR_CODE_INVALID_SETTINGS, "invalid crossover place %q", label)
+			}
+			rank, err := strconv.Atoi(label[1:])
+			if err != nil || rank < 1 || rank > advance {
+				return nil, Validation(ERROR_CODE_INVALID_SETTINGS, "invalid crossover place %q: place must be between 1 and %d", label, advance)
+			}
+			place := groupPlace{Group: int(label[0] - 'A'), Rank: rank - 1}
+			if place.Group >= groups {
+				return nil, Validation(ERROR_CODE_INVALID_SETTINGS, "invalid crossover place %q: there are only %d groups", label, groups)
+			}
+			if used[place] {
+				return nil, Validation(ERROR_CODE_INVALID_SETTINGS, "crossover place %q is used twice", label)
+			}
+			used[place] = true
+			places = append(places, place)
+		}
+	}
+	if len(places) != groups*advance {
+		return nil, Validation(ERROR_CODE_INVALID_SETTINGS, "crossover must cover all %d qualifying places, got %d", groups*advance, len(places))
+	}
+	return places, nil
+}
+
+func advancePerGroup(settings entity.TournamentSettings) int {
+	if settings.AdvancePerGroup == 0 {
+		return DEFAULT_ADVANCE_PER_GROUP
+	}
+	return settings.AdvancePerGroup
+}
+
+func groupName(group int) string {
+	return string(rune('A' + group))
+}
+
+// splitToGroups перемешивает команды и раскладывает их по группам поровну.
+func splitToGroups(rng *rand.Rand, teams []entity.Team, settings entity.TournamentSettings) ([][]entity.Team, error) {
+	count := settings.Groups
+	if count == 0 && settings.GroupSize > 0 {
+		count = (len(teams) + settings.GroupSize - 1) / settings.GroupSize
+	}
+	if count == 0 {
+		count = DEFAULT_GROUPS
+	}
+	if settings.GroupSize > 0 && len(teams) > count*settings.GroupSize {
+		return nil, InvalidState(ERROR_CODE_TOO_MANY_TEAMS, "expected at most %d teams for %d groups of %d", count*settings.GroupSize, count, settings.GroupSize)
+	}
+	if len(teams) < 2*count {
+		return nil, InvalidState(ERROR_CODE_NOT_ENOUGH_TEAMS, "expected at least %d teams, got %d", 2*count, len(teams))
+	}
+
+	rng.Shuffle(len(teams), func(i, j int) {
+		teams[i], teams[j] = teams[j], teams[i]
+	})
+
+	groups := make([][]entity.Team, count)
+	for i, team := range teams {
+		groups[i%count] = append(groups[i%count], team)
+	}
+
+	return groups, nil
+}
+
+func roundRobin(teams []entity.Team, gameType int) []entity.Game {
+	var games []entity.Game
+	for i := 0; i < len(teams); i++ {
+		for j := i + 1; j < len(teams); j++ {
+			game := newGame(gameType, teams[i], teams[j])
+			game.Position = len(games)
+			games = append(games, game)
+		}
+	}
+	return games
+}
+
+func gamesByGroup(games []entity.Game, group int) []entity.Game {
+	var result []entity.Game
+	for _, game := range games {
+		if game.Group == group {
+			result = append(result, game)
+		}
+	}
+	return result
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"testing"
+	"tournament/internal/entity"
+)
+
+func TestClassicFormatStages(t *testing.T) {
+	state := playFormat(t, ClassicFormat{}, FormatState{Teams: newTeams(16)}, firstTeamWins)
+
+	want := map[int]int{
+		entity.GAME_TYPE_GROUP:             56,
+		entity.GAME_TYPE_PLAYOFF_STAGE_1:   4,
+		entity.GAME_TYPE_PLAYOFF_SEMIFINAL: 2,
+		entity.GAME_TYPE_PLAYOFF_FINAL:     1,
+	}
+	for gameType, count := range want {
+		if got := len(state.GamesByType(gameType)); got != count {
+			t.Errorf("%d games of type %d, want %d", got, gameType, count)
+		}
+	}
+
+	winner, err := ClassicFormat{}.Winner(state)
+	if err != nil {
+		t.Fatalf("winner: %v", err)
+	}
+	final := state.GamesByType(entity.GAME_TYPE_PLAYOFF_FINAL)[0]
+	if winner.ID != final.Team1ID {
+		t.Errorf("winner %d, want final winner %d", winner.ID, final.Team1ID)
+	}
+}
+
+func TestClassicGroupSettings(t *testing.T) {
+	cases := []struct {
+		name     string
+		settings entity.TournamentSettings
+		teams    int
+		groups   []int
+		playoff  int
+	}{
+		{name: "defaults", teams: 16, groups: []int{8, 8}, playoff: 8},
+		{name: "four groups", settings: entity.TournamentSettings{Groups: 4, AdvancePerGroup: 2}, teams: 16, groups: []int{4, 4, 4, 4}, playoff: 8},
+		{name: "group size", settings: entity.TournamentSettings{GroupSize: 5, AdvancePerGroup: 2}, teams: 14, groups: []int{5, 5, 4}, playoff: 6},
+		{name: "uneven playoff", settings: entity.TournamentSettings{Groups: 3, AdvancePerGroup: 3}, teams: 12, groups: []int{4, 4, 4}, playoff: 9},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := (ClassicFormat{}).ValidateSettings(tc.settings); err != nil {
+				t.Fatalf("settings: %v", err)
+			}
+			state := FormatState{Tournament: entity.Tournament{Settings: tc.settings}, Teams: newTeams(tc.teams)}
+			state = playFormat(t, ClassicFormat{}, state, higherSeedWins)
+
+			for group, size := range tc.groups {
+				games := gamesByGroup(state.GamesByType(entity.GAME_TYPE_GROUP), group+1)
+				if len(games) != size*(size-1)/2 {
+					t.Errorf("group %s has %d games, want %d for %d teams", groupName(group), len(games), size*(size-1)/2, size)
+				}
+			}
+			if games := gamesByGroup(state.GamesByType(entity.GAME_TYPE_GROUP), len(tc.groups)+1); len(games) != 0 {
+				t.Errorf("%d games in an extra group", len(games))
+			}
+
+			seeds, err := ClassicFormat{}.playoffSeeds(state)
+			if err != nil {
+				t.Fatalf("playoff seeds: %v", err)
+			}
+			if len(seeds) != tc.playoff {
+				t.Errorf("%d playoff teams, want %d", len(seeds), tc.playoff)
+			}
+			if _, err := (ClassicFormat{}).Winner(state); err != nil {
+				t.Errorf("winner: %v", err)
+			}
+		})
+	}
+}
+
+func TestClassicCrossover(t *testing.T) {
+	settings := entity.TournamentSettings{Groups: 2, AdvancePerGroup: 2, Crossover: []string{"A1-B2", "B1-A2"}}
+	state := FormatState{Tournament: entity.Tournament{Settings: settings}, Teams: newTeams(8)}
+	state, _ = playStage(t, ClassicFormat{}, state, higherSeedWins)
+	_, playoff := playStage(t, ClassicFormat{}, state, higherSeedWins)
+
+	var places [][]Standing
+	for group := 1; group <= 2; group++ {
+		standings, err := CalculateStandings(state, gamesByGroup(state.GamesByType(entity.GAME_TYPE_GROUP), group), nil)
+		if err != nil {
+			t.Fatalf("group %d: %v", group, err)
+		}
+		places = append(places, standings)
+	}
+
+	if len(playoff) != 2 {
+		t.Fatalf("%d playoff games, want 2", len(playoff))
+	}
+	checkTeams(t, playoff[0], places[0][0].Team.ID, places[1][1].Team.ID)
+	checkTeams(t, playoff[1], places[1][0].Team.ID, places[0][1].Team.ID)
+}
+
+func TestClassicValidateSettings(t *testing.T) {
+	cases := []struct {
+		name     string
+		settings entity.TournamentSettings
+	}{
+		{name: "too many groups", settings: entity.TournamentSettings{Groups: 27}},
+		{name: "group of one", settings: entity.TournamentSettings{GroupSize: 1}},
+		{name: "negative advancement", settings: entity.TournamentSettings{AdvancePerGroup: -1}},
+		{name: "crossover not a power of two", settings: entity.TournamentSettings{Crossover: []string{"A1-B4", "B1-A4", "A2-B3"}}},
+		{name: "malformed crossover pair", settings: entity.TournamentSettings{Crossover: []string{"A1B4"}}},
+		{name: "place below advancement", settings: entity.TournamentSettings{AdvancePerGroup: 2, Crossover: []string{"A1-B3"}}},
+		{name: "unknown group", settings: entity.TournamentSettings{Crossover: []string{"A1-C1"}}},
+		{name: "place used twice", settings: entity.TournamentSettings{Crossover: []string{"A1-B2", "A1-B1"}}},
+		// число групп при заданном только group_size неизвестно до регистрации команд
+		{name: "crossover without groups", settings: entity.TournamentSettings{GroupSize: 4, AdvancePerGroup: 1, Crossover: []string{"A1-B1"}}},
+		{name: "crossover misses qualifying places", settings: entity.TournamentSettings{Groups: 4, AdvancePerGroup: 1, Crossover: []string{"A1-B1"}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := (ClassicFormat{}).ValidateSettings(tc.settings); err == nil {
+				t.Errorf("settings %+v: no error", tc.settings)
+			}
+		})
+	}
+
+	valid := entity.TournamentSettings{Groups: 4, AdvancePerGroup: 1, Crossover: []string{"A1-B1", "C1-D1"}}
+	if err := (ClassicFormat{}).ValidateSettings(valid); err != nil {
+		t.Errorf("settings %+v: %v", valid, err)
+	}
+}
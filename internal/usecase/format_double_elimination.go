@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"errors"
+	"tournament/internal/entity"
+)
+
+// DoubleEliminationFormat - плей-офф до двух поражений: верхняя и нижняя сетки и гранд-финал.
+// Посев по местам команд (Seed), непосеянные - по порядку регистрации. Если включен GrandFinalReset и гранд-финал выигрывает
+// команда из нижней сетки, играется второй гранд-финал (сброс сетки).
+type DoubleEliminationFormat struct{}
+
+func init() {
+	RegisterFormat(DoubleEliminationFormat{})
+}
+
+func (f DoubleEliminationFormat) Name() string {
+	return "double_elimination"
+}
+
+func (f DoubleEliminationFormat) ValidateSettings(settings entity.TournamentSettings) error {
+	return nil
+}
+
+// MaxTeams не зависит от настроек: сетка строится для любого количества команд, ограничение - общий MAX_TEAMS.
+func (f DoubleEliminationFormat) MaxTeams(settings entity.TournamentSettings) int {
+	return MAX_TEAMS
+}
+
+func (f DoubleEliminationFormat) NextStage(state FormatState) (*Stage, error) {
+	playoff, err := newDoubleEliminationBracket(len(state.Teams))
+	if err != nil {
+		return nil, err
+	}
+
+	stage, err := playoff.nextStage(state, state.Teams)
+	if err != nil || stage != nil {
+		return stage, err
+	}
+
+	if !state.Tournament.Settings.GrandFinalReset || len(state.GamesByType(entity.GAME_TYPE_GRAND_FINAL_RESET)) > 0 {
+		return nil, nil
+	}
+
+	// первым в гранд-финале всегда стоит победитель верхней сетки
+	grandFinal := state.GamesByType(entity.GAME_TYPE_GRAND_FINAL)[0]
+	if *grandFinal.WinnerId == grandFinal.Team1ID {
+		return nil, nil
+	}
+
+	return &Stage{Games: []entity.Game{{
+		Team1ID:           grandFinal.Team1ID,
+		Team2ID:           grandFinal.Team2ID,
+		GameType:          entity.GAME_TYPE_GRAND_FINAL_RESET,
+		Round:             1,
+		Team1SourceGameID: grandFinal.ID,
+		Team2SourceGameID: grandFinal.ID,
+	}}}, nil
+}
+
+func (f DoubleEliminationFormat) Winner(state FormatState) (*entity.Team, error) {
+	reset := state.GamesByType(entity.GAME_TYPE_GRAND_FINAL_RESET)
+	if len(reset) == 0 {
+		playoff, err := newDoubleEliminationBracket(len(state.Teams))
+		if err != nil {
+			return nil, err
+		}
+		return playoff.winner(state, state.Teams)
+	}
+
+	if reset[0].WinnerId == nil {
+		return nil, errors.New("grand final reset has not been played yet")
+	}
+	winner, err := state.Team(*reset[0].WinnerId)
+	if err != nil {
+		return nil, err
+	}
+	return &winner, nil
+}
+
+// Placements - первые два места по гранд-финалу (или его переигровке), остальные - по раунду
+// нижней сетки, в котором команда получила второе поражение: третье место у проигравшего финала нижней сетки.
+func (f DoubleEliminationFormat) Placements(state FormatState) ([]entity.Placement, error) {
+	playoff, err := newDoubleEliminationBracket(len(state.Teams))
+	if err != nil {
+		return nil, err
+	}
+
+	final := state.GamesByType(entity.GAME_TYPE_GRAND_FINAL_RESET)
+	if len(final) == 0 {
+		final = state.GamesByType(entity.GAME_TYPE_GRAND_FINAL)
+	}
+	if len(final) == 0 || final[0].WinnerId == nil {
+		return nil, errors.New("grand final has not been played yet")
+	}
+
+	loser := final[0].Team1ID
+	if loser == *final[0].WinnerId {
+		loser = final[0].Team2ID
+	}
+	placements, next := exactPlacements([]int{*final[0].WinnerId, loser}, 1)
+	eliminated, _ := playoff.eliminatedPlacements(newBracketResolver(state.Games, state.Teams), next)
+	return append(placements, eliminated...), nil
+}
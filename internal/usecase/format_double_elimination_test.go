@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"testing"
+	"tournament/internal/entity"
+)
+
+func TestDoubleEliminationRoutesLosersToLowerBracket(t *testing.T) {
+	state := FormatState{Teams: newTeams(4)}
+	state, first := playStage(t, DoubleEliminationFormat{}, state, firstTeamWins)
+	if len(first) != 2 {
+		t.Fatalf("%d games in the first stage, want 2", len(first))
+	}
+
+	state, second := playStage(t, DoubleEliminationFormat{}, state, firstTeamWins)
+	upperFinal := findGame(t, second, entity.GAME_TYPE_WINNERS_BRACKET, 2)
+	lower := findGame(t, second, entity.GAME_TYPE_LOSERS_BRACKET, 1)
+	checkTeams(t, upperFinal, first[0].Team1ID, first[1].Team1ID)
+	checkTeams(t, lower, first[0].Team2ID, first[1].Team2ID)
+
+	// проигравший финала верхней сетки получает второй шанс в финале нижней
+	state, third := playStage(t, DoubleEliminationFormat{}, state, firstTeamWins)
+	lowerFinal := findGame(t, third, entity.GAME_TYPE_LOSERS_BRACKET, 2)
+	checkTeams(t, lowerFinal, upperFinal.Team2ID, lower.Team1ID)
+
+	_, fourth := playStage(t, DoubleEliminationFormat{}, state, firstTeamWins)
+	grandFinal := findGame(t, fourth, entity.GAME_TYPE_GRAND_FINAL, 1)
+	if grandFinal.Team1ID != upperFinal.Team1ID || grandFinal.Team2ID != lowerFinal.Team1ID {
+		t.Errorf("grand final %d vs %d, want upper winner %d vs lower winner %d", grandFinal.Team1ID, grandFinal.Team2ID, upperFinal.Team1ID, lowerFinal.Team1ID)
+	}
+}
+
+func TestDoubleEliminationEliminatesAfterTwoLosses(t *testing.T) {
+	outcomes := map[string]func(game entity.Game) int{
+		"higher seed wins": higherSeedWins,
+		"lower seed wins":  lowerSeedWins,
+	}
+	for name, winner := range outcomes {
+		for teams := 2; teams <= 9; teams++ {
+			for _, reset := range []bool{false, true} {
+				state := FormatState{Teams: newTeams(teams)}
+				state.Tournament.Settings.GrandFinalReset = reset
+				state = playFormat(t, DoubleEliminationFormat{}, state, winner)
+
+				champion, err := DoubleEliminationFormat{}.Winner(state)
+				if err != nil {
+					t.Fatalf("%s, %d teams: winner: %v", name, teams, err)
+				}
+				losses := map[int]int{}
+				for _, game := range state.Games {
+					if !game.IsBye() {
+						losses[game.Team1ID+game.Team2ID-*game.WinnerId]++
+					}
+				}
+				for _, team := range state.Teams {
+					if team.ID == champion.ID && losses[team.ID] > 1 || team.ID != champion.ID && losses[team.ID] != 2 {
+						t.Errorf("%s, %d teams, reset %v: team %d lost %d times, champion %d", name, teams, reset, team.ID, losses[team.ID], champion.ID)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDoubleEliminationGrandFinalReset(t *testing.T) {
+	for _, reset := range []bool{false, true} {
+		state := FormatState{Teams: newTeams(4)}
+		state.Tournament.Settings.GrandFinalReset = reset
+		// из нижней сетки в гранд-финал выходит четвертый посев и выигрывает его
+		state = playFormat(t, DoubleEliminationFormat{}, state, func(game entity.Game) int {
+			if game.GameType == entity.GAME_TYPE_GRAND_FINAL {
+				return game.Team2ID
+			}
+			return higherSeedWins(game)
+		})
+
+		resets := state.GamesByType(entity.GAME_TYPE_GRAND_FINAL_RESET)
+		if reset != (len(resets) == 1) {
+			t.Errorf("reset %v: %d grand final resets played", reset, len(resets))
+		}
+		champion, err := DoubleEliminationFormat{}.Winner(state)
+		if err != nil {
+			t.Fatalf("reset %v: winner: %v", reset, err)
+		}
+		want := state.GamesByType(entity.GAME_TYPE_GRAND_FINAL)[0].Team2ID
+		if reset {
+			want = resets[0].Team1ID
+		}
+		if champion.ID != want {
+			t.Errorf("reset %v: winner %d, want %d", reset, champion.ID, want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import "tournament/internal/entity"
+
+// SingleEliminationFormat - плей-офф на выбывание для любого количества команд.
+// Посев по местам команд (Seed), непосеянные - по порядку регистрации; недостающие до степени двойки места - пропуски для лучших.
+type SingleEliminationFormat struct{}
+
+func init() {
+	RegisterFormat(SingleEliminationFormat{})
+}
+
+func (f SingleEliminationFormat) Name() string {
+	return "single_elimination"
+}
+
+func (f SingleEliminationFormat) ValidateSettings(settings entity.TournamentSettings) error {
+	return nil
+}
+
+// MaxTeams не зависит от настроек: сетка строится для любого количества команд, ограничение - общий MAX_TEAMS.
+func (f SingleEliminationFormat) MaxTeams(settings entity.TournamentSettings) int {
+	return MAX_TEAMS
+}
+
+func (f SingleEliminationFormat) NextStage(state FormatState) (*Stage, error) {
+	playoff, err := newSingleEliminationBracket(len(state.Teams), state.Tournament.Settings.ThirdPlaceMatch)
+	if err != nil {
+		return nil, err
+	}
+	return playoff.nextStage(state, state.Teams)
+}
+
+func (f SingleEliminationFormat) Winner(state FormatState) (*entity.Team, error) {
+	playoff, err := newSingleEliminationBracket(len(state.Teams), state.Tournament.Settings.ThirdPlaceMatch)
+	if err != nil {
+		return nil, err
+	}
+	return playoff.winner(state, state.Teams)
+}
+
+func (f SingleEliminationFormat) Placements(state FormatState) ([]entity.Placement, error) {
+	playoff, err := newSingleEliminationBracket(len(state.Teams), state.Tournament.Settings.ThirdPlaceMatch)
+	if err != nil {
+		return nil, err
+	}
+	placements, _, err := playoff.placements(state, state.Teams)
+	return placements, err
+}
@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"math/bits"
+	"slices"
+	"sort"
+	"tournament/internal/entity"
+)
+
+// SwissFormat - швейцарская система: раунды генерируются по одному, команды с равным
+// количеством очков играют между собой, повторные встречи исключаются.
+// При нечетном количестве команд одна из них получает пропуск (bye) и победу.
+type SwissFormat struct{}
+
+// SWISS_PAIRING_ATTEMPTS - предел перебора при составлении пар: после него играют соседи по таблице
+const SWISS_PAIRING_ATTEMPTS = 10000
+
+type SwissStanding struct {
+	Team            entity.Team `json:"team"`
+	Score           float64     `json:"score"`
+	Buchholz        float64     `json:"buchholz"`
+	SonnebornBerger float64     `json:"sonneborn_berger"`
+}
+
+func init() {
+	RegisterFormat(SwissFormat{})
+}
+
+func (f SwissFormat) Name() string {
+	return "swiss"
+}
+
+func (f SwissFormat) ValidateSettings(settings entity.TournamentSettings) error {
+	if settings.SwissRounds < 0 {
+		return Validation(ERROR_CODE_INVALID_SETTINGS, "swiss_rounds must be positive")
+	}
+	return nil
+}
+
+// MaxTeams при заданном swiss_rounds - 2^swiss_rounds: за столько раундов остается не больше одной
+// команды без поражений, как и при количестве раундов по умолчанию. Иначе раунды подбираются по командам.
+func (f SwissFormat) MaxTeams(settings entity.TournamentSettings) int {
+	if settings.SwissRounds > 0 && settings.SwissRounds < bits.Len(MAX_TEAMS) {
+		return 1 << settings.SwissRounds
+	}
+	return MAX_TEAMS
+}
+
+func (f SwissFormat) NextStage(state FormatState) (*Stage, error) {
+	rounds, err := f.rounds(state)
+	if err != nil {
+		return nil, err
+	}
+
+	round := 0
+	for _, game := range state.GamesByType(entity.GAME_TYPE_SWISS) {
+		round = max(round, game.Round)
+	}
+	if round >= rounds {
+		return nil, nil
+	}
+
+	standings, err := f.Standings(state)
+	if err != nil {
+		return nil, err
+	}
+
+	played := map[[2]int]bool{}
+	hadBye := map[int]bool{}
+	for _, game := range state.GamesByType(entity.GAME_TYPE_SWISS) {
+		if game.IsBye() {
+			hadBye[game.Team1ID] = true
+			continue
+		}
+		played[[2]int{game.Team1ID, game.Team2ID}] = true
+		played[[2]int{game.Team2ID, game.Team1ID}] = true
+	}
+
+	ranked := make([]int, 0, len(standings))
+	for _, standing := range standings {
+		ranked = append(ranked, standing.Team.ID)
+	}
+
+	stage := &Stage{}
+	// пропуск получает самая слабая команда, у которой его еще не было
+	if len(ranked)%2 == 1 {
+		bye := len(ranked) - 1
+		for i := len(ranked) - 1; i >= 0; i-- {
+			if !hadBye[ranked[i]] {
+				bye = i
+				break
+			}
+		}
+		winner := ranked[bye]
+		stage.Games = append(stage.Games, entity.Game{
+			Team1ID:  winner,
+			GameType: entity.GAME_TYPE_SWISS,
+			Round:    round + 1,
+			WinnerId: &winner,
+		})
+		ranked = slices.Delete(ranked, bye, bye+1)
+	}
+
+	pairs, ok := pairSwiss(ranked, played)
+	if !ok {
+		// без повторных встреч составить пары нельзя - играют соседи по таблице
+		pairs = nil
+		for i := 0; i+1 < len(ranked); i += 2 {
+			pairs = append(pairs, [2]int{ranked[i], ranked[i+1]})
+		}
+	}
+
+	for _, pair := range pairs {
+		stage.Games = append(stage.Games, entity.Game{
+			Team1ID:  pair[0],
+			Team2ID:  pair[1],
+			GameType: entity.GAME_TYPE_SWISS,
+			Round:    round + 1,
+		})
+	}
+	for i := range stage.Games {
+		stage.Games[i].Position = i
+	}
+
+	return stage, nil
+}
+
+func (f SwissFormat) Winner(state FormatState) (*entity.Team, error) {
+	standings, err := f.Standings(state)
+	if err != nil {
+		return nil, err
+	}
+	if len(standings) == 0 {
+		return nil, InvalidState(ERROR_CODE_NOT_ENOUGH_TEAMS, "tournament has no teams")
+	}
+	return &standings[0].Team, nil
+}
+
+// Placements - места по таблице, у каждой команды свое.
+func (f SwissFormat) Placements(state FormatState) ([]entity.Placement, error) {
+	standings, err := f.Standings(state)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(standings))
+	for _, standing := range standings {
+		ids = append(ids, standing.Team.ID)
+	}
+	placements, _ := exactPlacements(ids, 1)
+	return placements, nil
+}
+
+// Standings - таблица по очкам, затем по коэффициентам Бухгольца и Зоннеборна-Бергера.
+// Бухгольц - сумма очков соперников, Зоннеборн-Бергер - сумма очков обыгранных соперников
+// и половина очков соперников, с которыми сыграна ничья.
+func (f SwissFormat) Standings(state FormatState) ([]SwissStanding, error) {
+	games := state.GamesByType(entity.GAME_TYPE_SWISS)
+
+	// победа (и пропуск) - очко, ничья - пол-очка
+	scores := map[int]float64{}
+	for _, game := range games {
+		if game.IsDraw() {
+			scores[game.Team1ID] += 0.5
+			scores[game.Team2ID] += 0.5
+		} else if game.WinnerId != nil {
+			scores[*game.WinnerId]++
+		}
+	}
+
+	standings := make([]SwissStanding, 0, len(state.Teams))
+	for _, team := range state.Teams {
+		standing := SwissStanding{Team: team, Score: scores[team.ID]}
+		for _, game := range games {
+			if game.IsBye() || (game.Team1ID != team.ID && game.Team2ID != team.ID) {
+				continue
+			}
+			opponent := game.Team1ID
+			if opponent == team.ID {
+				opponent = game.Team2ID
+			}
+			standing.Buchholz += scores[opponent]
+			if game.IsDraw() {
+				standing.SonnebornBerger += scores[opponent] / 2
+			} else if game.WinnerId != nil && *game.WinnerId == team.ID {
+				standing.SonnebornBerger += scores[opponent]
+			}
+		}
+		standings = append(standings, standing)
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Buchholz != b.Buchholz {
+			return a.Buchholz > b.Buchholz
+		}
+		return a.SonnebornBerger > b.SonnebornBerger
+	})
+
+	return standings, nil
+}
+
+// rounds - количество раундов из настроек, по умолчанию log2 от количества команд с округлением вверх.
+func (f SwissFormat) rounds(state FormatState) (int, error) {
+	if len(state.Teams) < 2 {
+		return 0, InvalidState(ERROR_CODE_NOT_ENOUGH_TEAMS, "at least 2 teams are required for swiss, got %d", len(state.Teams))
+	}
+
+	rounds := state.Tournament.Settings.SwissRounds
+	if rounds == 0 {
+		rounds = bits.Len(uint(len(state.Teams) - 1))
+	}
+	if rounds < 0 || rounds >= len(state.Teams)+len(state.Teams)%2 {
+		return 0, Validation(ERROR_CODE_INVALID_SETTINGS, "swiss_rounds must be between 1 and %d", len(state.Teams)+len(state.Teams)%2-1)
+	}
+	return rounds, nil
+}
+
+// pairSwiss составляет пары по порядку таблицы: каждая команда играет с ближайшей
+// по таблице, с которой еще не встречалась. При тупике перебор откатывается назад,
+// но не более SWISS_PAIRING_ATTEMPTS раз.
+func pairSwiss(ranked []int, played map[[2]int]bool) ([][2]int, bool) {
+	attempts := SWISS_PAIRING_ATTEMPTS
+	return pairSwissWithin(ranked, played, &attempts)
+}
+
+func pairSwissWithin(ranked []int, played map[[2]int]bool, attempts *int) ([][2]int, bool) {
+	if len(ranked) == 0 {
+		return nil, true
+	}
+
+	first := ranked[0]
+	for i := 1; i < len(ranked); i++ {
+		if played[[2]int{first, ranked[i]}] {
+			continue
+		}
+		if *attempts <= 0 {
+			return nil, false
+		}
+		*attempts--
+
+		rest := make([]int, 0, len(ranked)-2)
+		rest = append(rest, ranked[1:i]...)
+		rest = append(rest, ranked[i+1:]...)
+		if pairs, ok := pairSwissWithin(rest, played, attempts); ok {
+			return append([][2]int{{first, ranked[i]}}, pairs...), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"math/bits"
+	"reflect"
+	"testing"
+	"tournament/internal/entity"
+)
+
+func TestPairSwiss(t *testing.T) {
+	cases := []struct {
+		name   string
+		played [][2]int
+		pairs  [][2]int
+		ok     bool
+	}{
+		{name: "neighbours", pairs: [][2]int{{1, 2}, {3, 4}}, ok: true},
+		{name: "skips a rematch", played: [][2]int{{1, 2}}, pairs: [][2]int{{1, 3}, {2, 4}}, ok: true},
+		{name: "backtracks from a dead end", played: [][2]int{{1, 2}, {2, 4}}, pairs: [][2]int{{1, 4}, {2, 3}}, ok: true},
+		{name: "everyone has met", played: [][2]int{{1, 2}, {1, 3}, {1, 4}}, ok: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			played := map[[2]int]bool{}
+			for _, pair := range tc.played {
+				played[pair] = true
+				played[[2]int{pair[1], pair[0]}] = true
+			}
+			pairs, ok := pairSwiss([]int{1, 2, 3, 4}, played)
+			if ok != tc.ok || !reflect.DeepEqual(pairs, tc.pairs) {
+				t.Errorf("pairs %v, %v, want %v, %v", pairs, ok, tc.pairs, tc.ok)
+			}
+		})
+	}
+}
+
+func TestPairSwissIsBounded(t *testing.T) {
+	// команды разбиты на две группы нечетного размера, и каждая уже сыграла со всеми из другой группы:
+	// пар без повторных встреч нет, но полный перебор вариантов внутри групп занял бы годы
+	var ranked []int
+	played := map[[2]int]bool{}
+	for i := 1; i <= 42; i++ {
+		ranked = append(ranked, i)
+		for j := 1; j <= 42; j++ {
+			if i%2 != j%2 {
+				played[[2]int{i, j}] = true
+			}
+		}
+	}
+
+	attempts := 100
+	if pairs, ok := pairSwissWithin(ranked, played, &attempts); ok || attempts != 0 {
+		t.Errorf("pairs %v, %v with %d attempts left, want no pairs and the budget spent", pairs, ok, attempts)
+	}
+	if _, ok := pairSwiss(ranked, played); ok {
+		t.Error("pairs without rematches found, want none")
+	}
+
+	// после исчерпания перебора играют соседи по таблице
+	state := FormatState{Teams: newTeams(42)}
+	for i := 1; i <= 42; i++ {
+		for j := i + 1; j <= 42; j++ {
+			if i%2 != j%2 {
+				game := entity.Game{ID: len(state.Games) + 1, Team1ID: i, Team2ID: j, GameType: entity.GAME_TYPE_SWISS, Round: 1}
+				if err := setGameResult(&game, 1, 0, nil); err != nil {
+					t.Fatalf("game %d: %v", game.ID, err)
+				}
+				state.Games = append(state.Games, game)
+			}
+		}
+	}
+	stage, err := SwissFormat{}.NextStage(state)
+	if err != nil {
+		t.Fatalf("next stage: %v", err)
+	}
+	if stage == nil || len(stage.Games) != 21 {
+		t.Fatalf("stage %+v, want 21 games", stage)
+	}
+}
+
+func TestSwissRounds(t *testing.T) {
+	for teams := 2; teams <= 16; teams++ {
+		state := playFormat(t, SwissFormat{}, FormatState{Teams: newTeams(teams)}, higherSeedWins)
+
+		rounds := bits.Len(uint(teams - 1))
+		met := map[[2]int]bool{}
+		byes := map[int]int{}
+		for round := 1; round <= rounds; round++ {
+			playing := map[int]int{}
+			for _, game := range state.Games {
+				if game.Round != round {
+					continue
+				}
+				playing[game.Team1ID]++
+				if game.IsBye() {
+					byes[game.Team1ID]++
+					continue
+				}
+				playing[game.Team2ID]++
+				pair := [2]int{min(game.Team1ID, game.Team2ID), max(game.Team1ID, game.Team2ID)}
+				if met[pair] {
+					t.Errorf("%d teams: rematch %v in round %d", teams, pair, round)
+				}
+				met[pair] = true
+			}
+			for _, team := range state.Teams {
+				if playing[team.ID] != 1 {
+					t.Errorf("%d teams: team %d plays %d times in round %d", teams, team.ID, playing[team.ID], round)
+				}
+			}
+		}
+		if len(state.Games) != rounds*((teams+1)/2) {
+			t.Errorf("%d teams: %d games, want %d rounds", teams, len(state.Games), rounds)
+		}
+		for team, count := range byes {
+			if count > 1 {
+				t.Errorf("%d teams: team %d got %d byes", teams, team, count)
+			}
+		}
+	}
+}
+
+func TestSwissStandings(t *testing.T) {
+	won := func(round int, team1 int, team2 int) entity.Game {
+		return entity.Game{Team1ID: team1, Team2ID: team2, GameType: entity.GAME_TYPE_SWISS, Round: round, WinnerId: &team1}
+	}
+	state := FormatState{Teams: newTeams(4), Games: []entity.Game{
+		won(1, 1, 2),
+		won(1, 3, 4),
+		won(2, 1, 3),
+		won(2, 4, 2),
+	}}
+
+	standings, err := SwissFormat{}.Standings(state)
+	if err != nil {
+		t.Fatalf("standings: %v", err)
+	}
+	// у 3 и 4 по очку, но соперники 3 набрали больше (Бухгольц)
+	want := []struct {
+		team            int
+		score           float64
+		buchholz        float64
+		sonnebornBerger float64
+	}{
+		{team: 1, score: 2, buchholz: 1, sonnebornBerger: 1},
+		{team: 3, score: 1, buchholz: 3, sonnebornBerger: 1},
+		{team: 4, score: 1, buchholz: 1, sonnebornBerger: 0},
+		{team: 2, score: 0, buchholz: 3, sonnebornBerger: 0},
+	}
+	for i, w := range want {
+		got := standings[i]
+		if got.Team.ID != w.team || got.Score != w.score || got.Buchholz != w.buchholz || got.SonnebornBerger != w.sonnebornBerger {
+			t.Errorf("row %d: team %d score %v buchholz %v sb %v, want team %d score %v buchholz %v sb %v",
+				i+1, got.Team.ID, got.Score, got.Buchholz, got.SonnebornBerger, w.team, w.score, w.buchholz, w.sonnebornBerger)
+		}
+	}
+}
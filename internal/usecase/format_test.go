@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"tournament/internal/entity"
+)
+
+func newTeams(count int) []entity.Team {
+	teams := make([]entity.Team, 0, count)
+	for i := 1; i <= count; i++ {
+		teams = append(teams, entity.Team{ID: i, Name: fmt.Sprintf("Team %d", i)})
+	}
+	return teams
+}
+
+// firstTeamWins - в каждом матче побеждает первая команда.
+func firstTeamWins(game entity.Game) int {
+	return game.Team1ID
+}
+
+// higherSeedWins и lowerSeedWins - исход матча по посеву: команды посеяны по порядку id.
+func higherSeedWins(game entity.Game) int {
+	return min(game.Team1ID, game.Team2ID)
+}
+
+func lowerSeedWins(game entity.Game) int {
+	return max(game.Team1ID, game.Team2ID)
+}
+
+// playFormat проводит турнир по формату до конца: матчи каждой стадии решаются функцией winner.
+func playFormat(t *testing.T, format Format, state FormatState, winner func(game entity.Game) int) FormatState {
+	t.Helper()
+	for stages := 0; ; stages++ {
+		if stages > 100 {
+			t.Fatalf("%s format did not finish in %d stages", format.Name(), stages)
+		}
+		var games []entity.Game
+		state, games = playStage(t, format, state, winner)
+		if games == nil {
+			return state
+		}
+	}
+}
+
+// playStage создает и сразу решает матчи следующей стадии со счетом 1:0, пропуски (bye) уже созданы с победителем.
+// Возвращает nil вместо матчей, если турнир завершен.
+func playStage(t *testing.T, format Format, state FormatState, winner func(game entity.Game) int) (FormatState, []entity.Game) {
+	t.Helper()
+	stage, err := format.NextStage(state)
+	if err != nil {
+		t.Fatalf("stage after %d games: %v", len(state.Games), err)
+	}
+	if stage == nil {
+		return state, nil
+	}
+	var games []entity.Game
+	for _, game := range stage.Games {
+		game.ID = len(state.Games) + 1
+		if game.IsBye() {
+			game.Status = entity.GAME_STATUS_FINISHED
+		} else {
+			team1Score, team2Score := 1, 0
+			if winner(game) == game.Team2ID {
+				team1Score, team2Score = 0, 1
+			}
+			if err := setGameResult(&game, team1Score, team2Score, nil); err != nil {
+				t.Fatalf("game %d: %v", game.ID, err)
+			}
+		}
+		state.Games = append(state.Games, game)
+		games = append(games, game)
+	}
+	return state, games
+}
+
+func TestGetFormat(t *testing.T) {
+	format, err := GetFormat(DEFAULT_FORMAT)
+	if err != nil {
+		t.Fatalf("default format: %v", err)
+	}
+	if format.Name() != DEFAULT_FORMAT {
+		t.Errorf("format name %q, want %q", format.Name(), DEFAULT_FORMAT)
+	}
+
+	if _, err := GetFormat("round_robin_deluxe"); err == nil {
+		t.Error("unknown format: no error")
+	}
+}
+
+var placementCases = []struct {
+	name     string
+	format   Format
+	settings entity.TournamentSettings
+	teams    int
+	podium   []int
+}{
+	{name: "classic", format: ClassicFormat{}, teams: 16},
+	{name: "classic with partial playoff", format: ClassicFormat{}, settings: entity.TournamentSettings{Groups: 3, AdvancePerGroup: 2}, teams: 12},
+	{name: "classic with crossover", format: ClassicFormat{}, settings: entity.TournamentSettings{Groups: 2, AdvancePerGroup: 2, Crossover: []string{"A1-B2", "B1-A2"}}, teams: 10},
+	// без матча за третье место проигравшие в полуфиналах делят третье место
+	{name: "single elimination", format: SingleEliminationFormat{}, teams: 8, podium: []int{1, 2, 3, 4}},
+	{name: "single elimination with third place", format: SingleEliminationFormat{}, settings: entity.TournamentSettings{ThirdPlaceMatch: true}, teams: 8, podium: []int{1, 2, 3}},
+	{name: "third place match with a bye", format: SingleEliminationFormat{}, settings: entity.TournamentSettings{ThirdPlaceMatch: true}, teams: 3, podium: []int{1, 2, 3}},
+	{name: "double elimination", format: DoubleEliminationFormat{}, teams: 6, podium: []int{1, 2, 3}},
+	{name: "double elimination with reset", format: DoubleEliminationFormat{}, settings: entity.TournamentSettings{GrandFinalReset: true}, teams: 7, podium: []int{1, 2, 3}},
+	// в швейцарской системе второе и третье места решает Бухгольц
+	{name: "swiss", format: SwissFormat{}, teams: 8, podium: []int{1, 2, 5}},
+	{name: "swiss with a bye", format: SwissFormat{}, teams: 9},
+}
+
+func TestPlacements(t *testing.T) {
+	for _, tc := range placementCases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := FormatState{Tournament: entity.Tournament{Settings: tc.settings}, Teams: newTeams(tc.teams)}
+			state = playFormat(t, tc.format, state, higherSeedWins)
+			placements, err := tc.format.Placements(state)
+			if err != nil {
+				t.Fatalf("placements: %v", err)
+			}
+			checkPlacements(t, placements, state.Teams)
+
+			result, err := tournamentResult(state, placements)
+			if err != nil {
+				t.Fatalf("result: %v", err)
+			}
+			winner, err := tc.format.Winner(state)
+			if err != nil {
+				t.Fatalf("winner: %v", err)
+			}
+			if result.Winner.ID != winner.ID {
+				t.Errorf("first place %d, winner %d", result.Winner.ID, winner.ID)
+			}
+			if tc.podium == nil {
+				return
+			}
+			// команды, делящие место, идут в любом порядке
+			var podium []int
+			for _, team := range result.Podium {
+				podium = append(podium, team.ID)
+			}
+			slices.Sort(podium)
+			if !slices.Equal(podium, tc.podium) {
+				t.Errorf("podium %v, want %v", podium, tc.podium)
+			}
+		})
+	}
+}
+
+// checkPlacements проверяет, что каждая команда получила одно место, а места идут без пропусков:
+// на месте "с-по" столько команд, сколько в нем мест.
+func checkPlacements(t *testing.T, placements []entity.Placement, teams []entity.Team) {
+	t.Helper()
+	placed := map[int]int{}
+	shared := map[[2]int]int{}
+	for _, placement := range placements {
+		placed[placement.TeamID]++
+		shared[[2]int{placement.PlaceFrom, placement.PlaceTo}]++
+	}
+	for _, team := range teams {
+		if placed[team.ID] != 1 {
+			t.Errorf("team %d placed %d times, want once", team.ID, placed[team.ID])
+		}
+	}
+
+	ranges := make([][2]int, 0, len(shared))
+	for places := range shared {
+		ranges = append(ranges, places)
+	}
+	slices.SortFunc(ranges, func(a, b [2]int) int { return a[0] - b[0] })
+	next := 1
+	for _, places := range ranges {
+		if places[0] != next || places[1] < places[0] {
+			t.Fatalf("places %d-%d, want to start at %d", places[0], places[1], next)
+		}
+		if width := places[1] - places[0] + 1; shared[places] != width {
+			t.Errorf("places %d-%d are shared by %d teams, want %d", places[0], places[1], shared[places], width)
+		}
+		next = places[1] + 1
+	}
+	if next != len(teams)+1 {
+		t.Errorf("places end at %d, want %d", next-1, len(teams))
+	}
+}
+
+func TestMaxTeams(t *testing.T) {
+	cases := []struct {
+		name     string
+		format   Format
+		settings entity.TournamentSettings
+		max      int
+	}{
+		{name: "classic without group size", format: ClassicFormat{}, max: MAX_TEAMS},
+		{name: "classic with group size", format: ClassicFormat{}, settings: entity.TournamentSettings{Groups: 3, GroupSize: 5}, max: 15},
+		{name: "classic with group size only", format: ClassicFormat{}, settings: entity.TournamentSettings{GroupSize: 6}, max: MAX_TEAMS},
+		{name: "single elimination", format: SingleEliminationFormat{}, max: MAX_TEAMS},
+		{name: "double elimination", format: DoubleEliminationFormat{}, max: MAX_TEAMS},
+		{name: "swiss with rounds by teams", format: SwissFormat{}, max: MAX_TEAMS},
+		{name: "swiss with 3 rounds", format: SwissFormat{}, settings: entity.TournamentSettings{SwissRounds: 3}, max: 8},
+		{name: "swiss with more rounds than needed", format: SwissFormat{}, settings: entity.TournamentSettings{SwissRounds: 10}, max: MAX_TEAMS},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.format.MaxTeams(tc.settings); got != tc.max {
+				t.Errorf("max teams %d, want %d", got, tc.max)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"tournament/internal/entity"
+)
+
+// ImportTeamsRequest - команды для регистрации одним запросом, например строки таблицы заявок.
+type ImportTeamsRequest struct {
+	Teams []AddTeamRequest `json:"teams"`
+	// Lines - номера строк файла, из которых прочитаны команды (CSV): ошибки тогда указывают
+	// на строку файла, а не на индекс команды
+	Lines []int `json:"-"`
+	// Invalid - ошибки полей, найденные при разборе запроса, по индексу команды. Такой импорт
+	// отклоняется, но строки все равно проверяются, чтобы вернуть все ошибки одним ответом.
+	Invalid map[int]map[string]error `json:"-"`
+}
+
+type ImportTeamsResponse struct {
+	StatusCode int           `json:"status_code"`
+	Teams      []entity.Team `json:"teams"`
+}
+
+// ImportTeams регистрирует все команды в одной транзакции: если хотя бы одна строка не подходит,
+// не регистрируется ни одна, а ошибка содержит причины по строкам (RowField).
+func (t *TournamentUseCase) ImportTeams(tournamentID int, req ImportTeamsRequest) (*ImportTeamsResponse, error) {
+	tournament, err := t.getTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tournament.Status != entity.TOURNAMENT_STATUS_REGISTRATION {
+		return nil, InvalidState(ERROR_CODE_REGISTRATION_CLOSED, "registration is closed, tournament is in %s", tournament.Status)
+	}
+	if len(req.Teams) == 0 {
+		return nil, Validation(ERROR_CODE_INVALID_IMPORT, "no teams to import")
+	}
+
+	registered, err := t.TournamentRepository.GetTeams(tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRoster(*tournament, len(registered)+len(req.Teams)); err != nil {
+		return nil, err
+	}
+
+	// каждая строка проверяется вместе с уже зарегистрированными командами и предыдущими строками
+	others := registered
+	teams := make([]entity.Team, 0, len(req.Teams))
+	fields := map[string]error{}
+	invalid := 0
+	for i, row := range req.Teams {
+		team := newTeam(tournament.ID, row)
+		rowErrors := map[string]error{}
+		if err := checkTeamName(&team, others); err != nil {
+			rowErrors["name"] = err
+		}
+		if err := checkTeamSeed(team, others); err != nil {
+			rowErrors["seed"] = err
+		}
+		// ошибки разбора точнее: например, seed не число, а не просто пустой
+		for field, err := range req.Invalid[i] {
+			rowErrors[field] = err
+		}
+		for field, err := range rowErrors {
+			fields[req.RowField(i, field)] = err
+		}
+		if len(rowErrors) > 0 {
+			invalid++
+		}
+		others = append(others, team)
+		teams = append(teams, team)
+	}
+	if invalid > 0 {
+		return nil, &Error{
+			Kind:   ErrValidation,
+			Code:   ERROR_CODE_INVALID_IMPORT,
+			Format: "%d of %d imported teams are invalid",
+			Args:   []any{invalid, len(req.Teams)},
+			Fields: fields,
+		}
+	}
+
+	err = t.inTransaction(tournament.ID, func(tx *TournamentUseCase, tournament entity.Tournament) error {
+		if tournament.Status != entity.TOURNAMENT_STATUS_REGISTRATION {
+			return InvalidState(ERROR_CODE_REGISTRATION_CLOSED, "registration is closed, tournament is in %s", tournament.Status)
+		}
+		for i, team := range teams {
+			res, err := tx.TournamentRepository.AddTeam(tournament.ID, team)
+			if err != nil {
+				return teamNameTaken(err, team)
+			}
+			teams[i] = *res
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImportTeamsResponse{
+		StatusCode: http.StatusOK,
+		Teams:      teams,
+	}, nil
+}
+
+// RowField - имя поля команды с индексом index в ошибках импорта: строка файла, если она известна,
+// иначе индекс команды в запросе.
+func (r ImportTeamsRequest) RowField(index int, field string) string {
+	if index < len(r.Lines) {
+		return ImportLineField(r.Lines[index], field)
+	}
+	return ImportRowField(index, field)
+}
+
+// ImportRowField - имя поля команды в ошибках импорта, индекс как в массиве запроса: rows[0].name.
+func ImportRowField(index int, field string) string {
+	return fmt.Sprintf("rows[%d].%s", index, field)
+}
+
+// ImportLineField - имя поля команды, прочитанной из строки line файла (с единицы): lines[2].name.
+func ImportLineField(line int, field string) string {
+	return fmt.Sprintf("lines[%d].%s", line, field)
+}
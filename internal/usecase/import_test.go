@@ -0,0 +1,118 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+)
+
+func TestImportTeams(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 2)
+
+	res, err := uc.ImportTeams(id, usecase.ImportTeamsRequest{Teams: []usecase.AddTeamRequest{
+		{Name: " Alpha ", Seed: 1, Region: "EU"},
+		{Name: "Bravo", Rating: 1200},
+	}})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(res.Teams) != 2 || res.Teams[0].Name != "Alpha" || res.Teams[0].Seed != 1 || res.Teams[0].Region != "EU" || res.Teams[1].Rating != 1200 {
+		t.Errorf("imported teams %+v", res.Teams)
+	}
+	teams, err := uc.ListTeams(id, usecase.ListRequest{})
+	if err != nil || teams.Pagination.Total != 4 {
+		t.Fatalf("teams after import: %+v, %v", teams, err)
+	}
+}
+
+func TestImportTeamsIsAllOrNothing(t *testing.T) {
+	uc := newUseCase()
+	id, teams := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 1)
+	if _, err := uc.ImportTeams(id, usecase.ImportTeamsRequest{Teams: []usecase.AddTeamRequest{{Name: "Seeded", Seed: 3}}}); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	_, err := uc.ImportTeams(id, usecase.ImportTeamsRequest{Teams: []usecase.AddTeamRequest{
+		{Name: "Alpha"},
+		{Name: teams[0].Name},
+		{Name: "X", Seed: 3},
+		{Name: "ALPHA"},
+	}})
+	if errorCode(err) != usecase.ERROR_CODE_INVALID_IMPORT {
+		t.Fatalf("error %v, want %s", err, usecase.ERROR_CODE_INVALID_IMPORT)
+	}
+	var domainErr *usecase.Error
+	if !errors.As(err, &domainErr) {
+		t.Fatalf("error %T, want *usecase.Error", err)
+	}
+	for _, field := range []string{
+		usecase.ImportRowField(1, "name"),
+		usecase.ImportRowField(2, "name"),
+		usecase.ImportRowField(2, "seed"),
+		usecase.ImportRowField(3, "name"),
+	} {
+		if domainErr.Fields[field] == nil {
+			t.Errorf("fields %v, want an error for %s", domainErr.Fields, field)
+		}
+	}
+	if domainErr.Fields[usecase.ImportRowField(0, "name")] != nil {
+		t.Errorf("valid row has an error: %v", domainErr.Fields)
+	}
+
+	list, err := uc.ListTeams(id, usecase.ListRequest{})
+	if err != nil || list.Pagination.Total != 2 {
+		t.Errorf("teams after failed import: %+v, %v", list, err)
+	}
+
+	cases := map[string]struct {
+		teams []usecase.AddTeamRequest
+		code  string
+	}{
+		"empty":    {code: usecase.ERROR_CODE_INVALID_IMPORT},
+		"too many": {teams: make([]usecase.AddTeamRequest, usecase.MAX_TEAMS), code: usecase.ERROR_CODE_TOURNAMENT_FULL},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := uc.ImportTeams(id, usecase.ImportTeamsRequest{Teams: tc.teams})
+			if errorCode(err) != tc.code {
+				t.Errorf("error %v, want %s", err, tc.code)
+			}
+		})
+	}
+
+	if _, err := uc.StartTournament(id, usecase.RunTournamentRequest{}); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	_, err = uc.ImportTeams(id, usecase.ImportTeamsRequest{Teams: []usecase.AddTeamRequest{{Name: "Late"}}})
+	if errorCode(err) != usecase.ERROR_CODE_REGISTRATION_CLOSED {
+		t.Errorf("import after start: error %v, want %s", err, usecase.ERROR_CODE_REGISTRATION_CLOSED)
+	}
+}
+
+func TestImportTeamsReportsParseErrorsWithRowErrors(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 0)
+
+	_, err := uc.ImportTeams(id, usecase.ImportTeamsRequest{
+		Teams:   []usecase.AddTeamRequest{{Name: "Alpha"}, {Name: "alpha"}, {Name: "Bravo"}},
+		Lines:   []int{2, 4, 5},
+		Invalid: map[int]map[string]error{2: {"rating": errors.New("rating must be a number")}},
+	})
+	var domainErr *usecase.Error
+	if !errors.As(err, &domainErr) || domainErr.Code != usecase.ERROR_CODE_INVALID_IMPORT {
+		t.Fatalf("error %v, want %s", err, usecase.ERROR_CODE_INVALID_IMPORT)
+	}
+	if len(domainErr.Fields) != 2 || domainErr.Fields[usecase.ImportLineField(4, "name")] == nil || domainErr.Fields[usecase.ImportLineField(5, "rating")] == nil {
+		t.Errorf("fields %v, want errors for lines 4 and 5", domainErr.Fields)
+	}
+	if len(domainErr.Args) != 2 || domainErr.Args[0] != 2 {
+		t.Errorf("args %v, want 2 invalid teams", domainErr.Args)
+	}
+
+	list, err := uc.ListTeams(id, usecase.ListRequest{})
+	if err != nil || list.Pagination.Total != 0 {
+		t.Errorf("teams after failed import: %+v, %v", list, err)
+	}
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"tournament/internal/entity"
+)
+
+// tournamentTransitions - допустимые переходы между этапами турнира.
+// Из посева турнир попадает в групповой этап или сразу в плей-офф, в зависимости от формата.
+var tournamentTransitions = map[string][]string{
+	entity.TOURNAMENT_STATUS_REGISTRATION: {entity.TOURNAMENT_STATUS_SEEDING, entity.TOURNAMENT_STATUS_CANCELLED},
+	entity.TOURNAMENT_STATUS_SEEDING:      {entity.TOURNAMENT_STATUS_GROUP_STAGE, entity.TOURNAMENT_STATUS_PLAYOFFS, entity.TOURNAMENT_STATUS_CANCELLED},
+	entity.TOURNAMENT_STATUS_GROUP_STAGE:  {entity.TOURNAMENT_STATUS_PLAYOFFS, entity.TOURNAMENT_STATUS_FINISHED, entity.TOURNAMENT_STATUS_CANCELLED},
+	entity.TOURNAMENT_STATUS_PLAYOFFS:     {entity.TOURNAMENT_STATUS_FINISHED, entity.TOURNAMENT_STATUS_CANCELLED},
+}
+
+// setStatus переводит турнир на этап status и сохраняет его.
+func (t *TournamentUseCase) setStatus(tournament entity.Tournament, status string) (*entity.Tournament, error) {
+	if tournament.Status == status {
+		return &tournament, nil
+	}
+	if !slices.Contains(tournamentTransitions[tournament.Status], status) {
+		return nil, InvalidState(ERROR_CODE_INVALID_STATUS_TRANSITION, "tournament cannot move from %s to %s", tournament.Status, status)
+	}
+	tournament.Status = status
+	return t.TournamentRepository.Update(tournament)
+}
+
+// syncStatus переводит турнир на этап по созданным матчам: пока есть только матчи
+// группового этапа - групповой этап, с первым матчем плей-офф - плей-офф.
+func (t *TournamentUseCase) syncStatus(tournament entity.Tournament, finished bool) (*entity.Tournament, error) {
+	if finished {
+		return t.setStatus(tournament, entity.TOURNAMENT_STATUS_FINISHED)
+	}
+
+	games, err := t.GameRepository.GetByTournament(tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	status := entity.TOURNAMENT_STATUS_GROUP_STAGE
+	for _, game := range games {
+		if !game.IsGroupStage() {
+			status = entity.TOURNAMENT_STATUS_PLAYOFFS
+			break
+		}
+	}
+	return t.setStatus(tournament, status)
+}
+
+// advance переводит турнир на один шаг вперед: закрывает регистрацию, создает первую стадию
+// после посева, а во время турнира создает следующую стадию, когда текущая сыграна.
+// Несыгранные матчи текущей стадии доигрывает симулятор, только если simulate, иначе шаг отклоняется.
+func (t *TournamentUseCase) advance(tournament entity.Tournament, seed *int64, simulate bool) (*entity.Tournament, []entity.Game, error) {
+	switch tournament.Status {
+	case entity.TOURNAMENT_STATUS_REGISTRATION:
+		teams, err := t.TournamentRepository.GetTeams(tournament.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(teams) < 2 {
+			return nil, nil, InvalidState(ERROR_CODE_NOT_ENOUGH_TEAMS, "at least 2 teams are required to close registration, got %d", len(teams))
+		}
+
+		res, err := t.fixSeed(tournament, seed)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := t.checkFirstStage(*res); err != nil {
+			return nil, nil, err
+		}
+		res, err = t.setStatus(*res, entity.TOURNAMENT_STATUS_SEEDING)
+		return res, nil, err
+
+	case entity.TOURNAMENT_STATUS_SEEDING, entity.TOURNAMENT_STATUS_GROUP_STAGE, entity.TOURNAMENT_STATUS_PLAYOFFS:
+		res, err := t.fixSeed(tournament, seed)
+		if err != nil {
+			return nil, nil, err
+		}
+		if res.Status != entity.TOURNAMENT_STATUS_SEEDING {
+			if simulate {
+				err = t.simulateStage(*res)
+			} else {
+				err = t.checkStagePlayed(*res)
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		games, finished, err := t.advanceStages(*res)
+		if err != nil {
+			return nil, nil, err
+		}
+		res, err = t.syncStatus(*res, finished)
+		return res, games, err
+
+	case entity.TOURNAMENT_STATUS_FINISHED:
+		return nil, nil, InvalidState(ERROR_CODE_TOURNAMENT_FINISHED, "tournament is already finished")
+	case entity.TOURNAMENT_STATUS_CANCELLED:
+		return nil, nil, InvalidState(ERROR_CODE_TOURNAMENT_CANCELLED, "tournament is cancelled")
+	}
+	return nil, nil, fmt.Errorf("unknown tournament status %q", tournament.Status)
+}
+
+// checkStagePlayed проверяет, что у всех матчей турнира есть результат: без симулятора
+// следующую стадию можно создать только после того, как сообщены результаты текущей.
+func (t *TournamentUseCase) checkStagePlayed(tournament entity.Tournament) error {
+	games, err := t.GameRepository.GetByTournament(tournament.ID)
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for _, game := range games {
+		if !game.IsPlayed() {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return InvalidState(ERROR_CODE_GAMES_PENDING, "%d games of the current stage are not finished yet", pending)
+	}
+	return nil
+}
+
+// checkFirstStage строит первую стадию, ничего не сохраняя: из посева нельзя вернуться к регистрации,
+// поэтому команды и настройки, с которыми формат не может начать турнир, отклоняются заранее.
+func (t *TournamentUseCase) checkFirstStage(tournament entity.Tournament) error {
+	format, err := GetFormat(tournament.Format)
+	if err != nil {
+		return err
+	}
+	state, err := t.formatState(tournament)
+	if err != nil {
+		return err
+	}
+	_, err = format.NextStage(*state)
+	return err
+}
+
+// advanceStep выполняет шаг advance в одной транзакции: результаты стадии и матчи следующей сохраняются вместе.
+func (t *TournamentUseCase) advanceStep(tournamentID int, seed *int64, simulate bool) (*entity.Tournament, []entity.Game, error) {
+	var res *entity.Tournament
+	var games []entity.Game
+	err := t.inTransaction(tournamentID, func(tx *TournamentUseCase, tournament entity.Tournament) error {
+		var err error
+		res, games, err = tx.advance(tournament, seed, simulate)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, games, nil
+}
+
+// inProgress - принимаются ли сейчас результаты матчей.
+func inProgress(tournament entity.Tournament) bool {
+	return tournament.Status == entity.TOURNAMENT_STATUS_GROUP_STAGE || tournament.Status == entity.TOURNAMENT_STATUS_PLAYOFFS
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"slices"
+	"testing"
+	"tournament/internal/entity"
+)
+
+func TestTournamentTransitions(t *testing.T) {
+	statuses := []string{
+		entity.TOURNAMENT_STATUS_REGISTRATION,
+		entity.TOURNAMENT_STATUS_SEEDING,
+		entity.TOURNAMENT_STATUS_GROUP_STAGE,
+		entity.TOURNAMENT_STATUS_PLAYOFFS,
+		entity.TOURNAMENT_STATUS_FINISHED,
+		entity.TOURNAMENT_STATUS_CANCELLED,
+	}
+	// репозиторий не нужен: недопустимый переход отклоняется до сохранения, а тот же этап не сохраняется
+	uc := &TournamentUseCase{}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			allowed := slices.Contains(tournamentTransitions[from], to)
+			if allowed {
+				continue
+			}
+			res, err := uc.setStatus(entity.Tournament{Status: from}, to)
+			if from == to {
+				if err != nil || res.Status != to {
+					t.Errorf("%s to itself: %v", from, err)
+				}
+				continue
+			}
+			if err == nil {
+				t.Errorf("%s to %s: no error", from, to)
+			}
+		}
+	}
+
+	// завершенный и отмененный турнир не меняет этап
+	for _, status := range []string{entity.TOURNAMENT_STATUS_FINISHED, entity.TOURNAMENT_STATUS_CANCELLED} {
+		if next := tournamentTransitions[status]; len(next) != 0 {
+			t.Errorf("%s can move to %v", status, next)
+		}
+	}
+	// отменить можно любой незавершенный турнир
+	for _, status := range statuses[:4] {
+		if !slices.Contains(tournamentTransitions[status], entity.TOURNAMENT_STATUS_CANCELLED) {
+			t.Errorf("%s cannot be cancelled", status)
+		}
+	}
+}
+
+func TestIsGroupStage(t *testing.T) {
+	for gameType, want := range map[int]bool{
+		entity.GAME_TYPE_GROUP:               true,
+		entity.GAME_TYPE_SWISS:               true,
+		entity.GAME_TYPE_DIVISION_A:          true,
+		entity.GAME_TYPE_PLAYOFF_STAGE_1:     false,
+		entity.GAME_TYPE_WINNERS_BRACKET:     false,
+		entity.GAME_TYPE_PLAYOFF_THIRD_PLACE: false,
+	} {
+		if got := (entity.Game{GameType: gameType}).IsGroupStage(); got != want {
+			t.Errorf("game type %d: group stage %v, want %v", gameType, got, want)
+		}
+	}
+}
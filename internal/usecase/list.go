@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"tournament/internal/entity"
+)
+
+const DEFAULT_PER_PAGE = 20
+const MAX_PER_PAGE = 100
+
+// поля, по которым можно сортировать списки
+var (
+	TournamentSortFields = []string{"id", "name", "status"}
+	TeamSortFields       = []string{"id", "name", "rating", "seed"}
+	GameSortFields       = []string{"id", "stage", "round", "game_type", "status"}
+)
+
+// ListRequest - страница списка. Sort - поле сортировки, с "-" в начале - по убыванию, например "-rating".
+type ListRequest struct {
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	PerPage int    `form:"per_page" binding:"omitempty,min=1,max=100"`
+	Sort    string `form:"sort"`
+}
+
+// ListOptions - страница и сортировка для репозиториев. Sort - одно из полей *SortFields,
+// при равенстве записи упорядочиваются по id.
+type ListOptions struct {
+	Limit  int
+	Offset int
+	Sort   string
+	Desc   bool
+}
+
+type Pagination struct {
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
+	Total   int `json:"total"`
+}
+
+type TournamentFilter struct {
+	Status string
+}
+
+// GameFilter - условия отбора матчей турнира, нулевые значения не ограничивают выборку.
+type GameFilter struct {
+	GameType int
+	Status   int
+}
+
+type ListTournamentsRequest struct {
+	ListRequest
+	Status string `form:"status"`
+}
+
+// ListGamesRequest - Stage - название стадии из GameTypeNames (group, final, ...),
+// Status - scheduled, in_progress или finished.
+type ListGamesRequest struct {
+	ListRequest
+	Stage  string `form:"stage"`
+	Status string `form:"status"`
+}
+
+type ListTournamentsResponse struct {
+	StatusCode  int                 `json:"status_code"`
+	Tournaments []entity.Tournament `json:"tournaments"`
+	Pagination  Pagination          `json:"pagination"`
+}
+
+type GetTournamentResponse struct {
+	StatusCode int                `json:"status_code"`
+	Tournament *entity.Tournament `json:"tournament"`
+}
+
+type ListTeamsResponse struct {
+	StatusCode int           `json:"status_code"`
+	Teams      []entity.Team `json:"teams"`
+	Pagination Pagination    `json:"pagination"`
+}
+
+type ListGamesResponse struct {
+	StatusCode int           `json:"status_code"`
+	Games      []entity.Game `json:"games"`
+	Pagination Pagination    `json:"pagination"`
+}
+
+// options проверяет сортировку и переводит номер страницы в смещение.
+func (r ListRequest) options(sortable []string) (ListOptions, error) {
+	opts := ListOptions{Sort: "id"}
+	if r.Sort != "" {
+		opts.Sort, opts.Desc = strings.CutPrefix(r.Sort, "-")
+		if !slices.Contains(sortable, opts.Sort) {
+			return ListOptions{}, Validation(ERROR_CODE_INVALID_SORT, "cannot sort by %q, expected one of: %s", opts.Sort, strings.Join(sortable, ", "))
+		}
+	}
+
+	opts.Limit = r.PerPage
+	if opts.Limit == 0 {
+		opts.Limit = DEFAULT_PER_PAGE
+	}
+	opts.Limit = min(opts.Limit, MAX_PER_PAGE)
+	opts.Offset = (max(r.Page, 1) - 1) * opts.Limit
+	return opts, nil
+}
+
+func newPagination(opts ListOptions, total int) Pagination {
+	return Pagination{
+		Page:    opts.Offset/opts.Limit + 1,
+		PerPage: opts.Limit,
+		Total:   total,
+	}
+}
+
+// gameFilter переводит названия стадии и статуса из запроса в типы матчей.
+func (r ListGamesRequest) gameFilter() (GameFilter, error) {
+	var filter GameFilter
+	if r.Stage != "" {
+		for gameType, name := range entity.GameTypeNames {
+			if name == r.Stage {
+				filter.GameType = gameType
+			}
+		}
+		if filter.GameType == 0 {
+			return GameFilter{}, Validation(ERROR_CODE_UNKNOWN_STAGE, "unknown stage %q", r.Stage)
+		}
+	}
+	if r.Status != "" {
+		for status, name := range entity.GameStatusNames {
+			if name == r.Status {
+				filter.Status = status
+			}
+		}
+		if filter.Status == 0 {
+			return GameFilter{}, Validation(ERROR_CODE_UNKNOWN_GAME_STATUS, "unknown game status %q", r.Status)
+		}
+	}
+	return filter, nil
+}
+
+func (t *TournamentUseCase) ListTournaments(req ListTournamentsRequest) (*ListTournamentsResponse, error) {
+	opts, err := req.options(TournamentSortFields)
+	if err != nil {
+		return nil, err
+	}
+
+	tournaments, total, err := t.TournamentRepository.List(TournamentFilter{Status: req.Status}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListTournamentsResponse{
+		StatusCode:  http.StatusOK,
+		Tournaments: tournaments,
+		Pagination:  newPagination(opts, total),
+	}, nil
+}
+
+func (t *TournamentUseCase) GetTournament(tournamentID int) (*GetTournamentResponse, error) {
+	tournament, err := t.getTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetTournamentResponse{
+		StatusCode: http.StatusOK,
+		Tournament: tournament,
+	}, nil
+}
+
+func (t *TournamentUseCase) ListTeams(tournamentID int, req ListRequest) (*ListTeamsResponse, error) {
+	opts, err := req.options(TeamSortFields)
+	if err != nil {
+		return nil, err
+	}
+
+	tournament, err := t.getTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, total, err := t.TournamentRepository.ListTeams(tournament.ID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListTeamsResponse{
+		StatusCode: http.StatusOK,
+		Teams:      teams,
+		Pagination: newPagination(opts, total),
+	}, nil
+}
+
+func (t *TournamentUseCase) ListGames(tournamentID int, req ListGamesRequest) (*ListGamesResponse, error) {
+	opts, err := req.options(GameSortFields)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := req.gameFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	tournament, err := t.getTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	games, total, err := t.GameRepository.List(tournament.ID, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListGamesResponse{
+		StatusCode: http.StatusOK,
+		Games:      games,
+		Pagination: newPagination(opts, total),
+	}, nil
+}
@@ -0,0 +1,120 @@
+package usecase_test
+
+import (
+	"fmt"
+	"testing"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+)
+
+func teamNames(teams []entity.Team) []string {
+	names := make([]string, 0, len(teams))
+	for _, team := range teams {
+		names = append(names, team.Name)
+	}
+	return names
+}
+
+func TestListTeams(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 5)
+
+	cases := []struct {
+		req        usecase.ListRequest
+		names      []string
+		pagination usecase.Pagination
+	}{
+		{req: usecase.ListRequest{}, names: []string{"Team 1", "Team 2", "Team 3", "Team 4", "Team 5"}, pagination: usecase.Pagination{Page: 1, PerPage: usecase.DEFAULT_PER_PAGE, Total: 5}},
+		{req: usecase.ListRequest{Page: 2, PerPage: 2}, names: []string{"Team 3", "Team 4"}, pagination: usecase.Pagination{Page: 2, PerPage: 2, Total: 5}},
+		{req: usecase.ListRequest{Page: 4, PerPage: 2}, names: []string{}, pagination: usecase.Pagination{Page: 4, PerPage: 2, Total: 5}},
+		{req: usecase.ListRequest{Sort: "-name", PerPage: 3}, names: []string{"Team 5", "Team 4", "Team 3"}, pagination: usecase.Pagination{Page: 1, PerPage: 3, Total: 5}},
+		// рейтинги 1050, 1100, 1150, 1200, 1000: при равенстве порядок по id
+		{req: usecase.ListRequest{Sort: "rating"}, names: []string{"Team 5", "Team 1", "Team 2", "Team 3", "Team 4"}, pagination: usecase.Pagination{Page: 1, PerPage: usecase.DEFAULT_PER_PAGE, Total: 5}},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%+v", tc.req), func(t *testing.T) {
+			res, err := uc.ListTeams(id, tc.req)
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if names := teamNames(res.Teams); fmt.Sprint(names) != fmt.Sprint(tc.names) {
+				t.Errorf("teams %v, want %v", names, tc.names)
+			}
+			if res.Pagination != tc.pagination {
+				t.Errorf("pagination %+v, want %+v", res.Pagination, tc.pagination)
+			}
+		})
+	}
+
+	if _, err := uc.ListTeams(id, usecase.ListRequest{Sort: "password"}); errorCode(err) != usecase.ERROR_CODE_INVALID_SORT {
+		t.Errorf("sort by unknown field: error %v, want %s", err, usecase.ERROR_CODE_INVALID_SORT)
+	}
+	if _, err := uc.ListTeams(id+1, usecase.ListRequest{}); errorCode(err) != usecase.ERROR_CODE_TOURNAMENT_NOT_FOUND {
+		t.Errorf("teams of missing tournament: error %v, want %s", err, usecase.ERROR_CODE_TOURNAMENT_NOT_FOUND)
+	}
+}
+
+func TestListTournaments(t *testing.T) {
+	uc := newUseCase()
+	for i := 0; i < 3; i++ {
+		createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 2)
+	}
+	if _, err := uc.AdvanceTournament(2, usecase.RunTournamentRequest{}); err != nil {
+		t.Fatalf("advance: %v", err)
+	}
+
+	res, err := uc.ListTournaments(usecase.ListTournamentsRequest{Status: entity.TOURNAMENT_STATUS_REGISTRATION})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(res.Tournaments) != 2 || res.Tournaments[0].ID != 1 || res.Tournaments[1].ID != 3 || res.Pagination.Total != 2 {
+		t.Errorf("tournaments in registration %+v, total %d", res.Tournaments, res.Pagination.Total)
+	}
+
+	res, err = uc.ListTournaments(usecase.ListTournamentsRequest{ListRequest: usecase.ListRequest{Sort: "-id", PerPage: 1}})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(res.Tournaments) != 1 || res.Tournaments[0].ID != 3 || res.Pagination.Total != 3 {
+		t.Errorf("last tournament %+v, total %d", res.Tournaments, res.Pagination.Total)
+	}
+}
+
+func TestListGames(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 4)
+	started, err := uc.StartTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+	reportResult(t, uc, id, started.Games[0], 1, 0)
+
+	cases := []struct {
+		req   usecase.ListGamesRequest
+		games int
+	}{
+		{req: usecase.ListGamesRequest{}, games: 2},
+		{req: usecase.ListGamesRequest{Stage: "semifinal"}, games: 2},
+		{req: usecase.ListGamesRequest{Stage: "final"}, games: 0},
+		{req: usecase.ListGamesRequest{Status: "finished"}, games: 1},
+		{req: usecase.ListGamesRequest{Stage: "semifinal", Status: "scheduled"}, games: 1},
+	}
+	for _, tc := range cases {
+		res, err := uc.ListGames(id, tc.req)
+		if err != nil {
+			t.Fatalf("list %+v: %v", tc.req, err)
+		}
+		if len(res.Games) != tc.games || res.Pagination.Total != tc.games {
+			t.Errorf("list %+v: %d games, total %d, want %d", tc.req, len(res.Games), res.Pagination.Total, tc.games)
+		}
+	}
+
+	for req, code := range map[usecase.ListGamesRequest]string{
+		{Stage: "quarterfinal"}: usecase.ERROR_CODE_UNKNOWN_STAGE,
+		{Status: "postponed"}:   usecase.ERROR_CODE_UNKNOWN_GAME_STATUS,
+	} {
+		if _, err := uc.ListGames(id, req); errorCode(err) != code {
+			t.Errorf("list %+v: error %v, want %s", req, err, code)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
+)
+
+// назначение генератора, чтобы разные операции турнира не делили одну последовательность
+const (
+	RAND_GROUPS = iota + 1
+	RAND_GAME
+)
+
+// seededRand - генератор для одной операции турнира, производный от сида турнира и ключа операции.
+// Одинаковые сид и ключ всегда дают одну и ту же последовательность, поэтому результат
+// не зависит от порядка, в котором генерируются стадии и разыгрываются матчи.
+func seededRand(seed int64, key ...int) *rand.Rand {
+	hash := fnv.New64a()
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, uint64(seed))
+	hash.Write(buf)
+	for _, k := range key {
+		binary.LittleEndian.PutUint64(buf, uint64(k))
+		hash.Write(buf)
+	}
+	return rand.New(rand.NewSource(int64(hash.Sum64())))
+}
+
+// Rand - генератор для операции формата, производный от сида турнира.
+func (s FormatState) Rand(key ...int) *rand.Rand {
+	var seed int64
+	if s.Tournament.Seed != nil {
+		seed = *s.Tournament.Seed
+	}
+	return seededRand(seed, key...)
+}
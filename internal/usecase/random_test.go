@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"tournament/internal/entity"
+)
+
+func TestSeededRand(t *testing.T) {
+	if a, b := seededRand(42, RAND_GAME, 7).Int63(), seededRand(42, RAND_GAME, 7).Int63(); a != b {
+		t.Errorf("same seed and key: %d and %d", a, b)
+	}
+	if a, b := seededRand(42, RAND_GAME, 7).Int63(), seededRand(42, RAND_GAME, 8).Int63(); a == b {
+		t.Error("different keys give the same sequence")
+	}
+	if a, b := seededRand(42, RAND_GROUPS).Int63(), seededRand(43, RAND_GROUPS).Int63(); a == b {
+		t.Error("different seeds give the same sequence")
+	}
+}
+
+func TestGroupStageIsReproducibleWithSeed(t *testing.T) {
+	groupStage := func(seed int64) *Stage {
+		state := FormatState{Tournament: entity.Tournament{Seed: &seed}, Teams: newTeams(16)}
+		stage, err := ClassicFormat{}.NextStage(state)
+		if err != nil {
+			t.Fatalf("group stage: %v", err)
+		}
+		return stage
+	}
+
+	if first, second := groupStage(1), groupStage(1); !reflect.DeepEqual(first, second) {
+		t.Error("same seed drew different groups")
+	}
+	if first, second := groupStage(1), groupStage(2); reflect.DeepEqual(first, second) {
+		t.Error("different seeds drew the same groups")
+	}
+}
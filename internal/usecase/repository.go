@@ -1,20 +1,49 @@
 package usecase
 
-import "tournament/internal/entity"
+import (
+	"errors"
+	"tournament/internal/entity"
+)
+
+// ErrDuplicate - запись не сохранена, потому что нарушает ограничение уникальности хранилища.
+// Об отсутствии записи репозитории сообщают через sql.ErrNoRows.
+var ErrDuplicate = errors.New("duplicate record")
 
 type TournamentRepository interface {
 	Create(tournament entity.Tournament) (*entity.Tournament, error)
 	Delete(tournament entity.Tournament) error
 	GetById(id int) (*entity.Tournament, error)
+	Update(tournament entity.Tournament) (*entity.Tournament, error)
+	// List возвращает страницу турниров и общее количество подходящих под фильтр
+	List(filter TournamentFilter, opts ListOptions) ([]entity.Tournament, int, error)
+	// AddTeam и UpdateTeam возвращают ErrDuplicate, если в турнире уже есть команда с таким названием без учета регистра
 	AddTeam(tournamentID int, team entity.Team) (*entity.Team, error)
+	GetTeam(id int) (*entity.Team, error)
+	UpdateTeam(team entity.Team) (*entity.Team, error)
+	DeleteTeam(team entity.Team) error
 	GetTeams(tournamentId int) ([]entity.Team, error)
+	ListTeams(tournamentID int, opts ListOptions) ([]entity.Team, int, error)
+	AddLots(tournamentID int, lots []entity.Lot) error
+	GetLots(tournamentID int) ([]entity.Lot, error)
+	SavePlacements(tournamentID int, placements []entity.Placement) error
+	GetPlacements(tournamentID int) ([]entity.Placement, error)
 }
 
 type GameRepository interface {
-	Create(tournamentID int, team1 entity.Team, team2 entity.Team, gameType int) (*entity.Game, error)
+	// Create возвращает ErrDuplicate, если в турнире уже есть матч того же типа, группы, раунда и позиции
+	Create(game entity.Game) (*entity.Game, error)
+	GetById(id int) (*entity.Game, error)
+	GetByTournament(tournamentID int) ([]entity.Game, error)
 	GetByTypeGames(tournamentID int, gameType int) ([]entity.Game, error)
+	List(tournamentID int, filter GameFilter, opts ListOptions) ([]entity.Game, int, error)
+	// Update сохраняет результат только еще не сыгранного матча, для завершенного возвращает sql.ErrNoRows
 	Update(game entity.Game) (*entity.Game, error)
-	GetTopTeams(tournamentID int, gameType int) ([]entity.Team, error)
-	GetWinnersByType(tournamentID int, gameType int) ([]entity.Team, error)
-	GetTop4WinnersByType(tournamentID int, gameType int) ([]entity.Team, error)
+}
+
+// UnitOfWork выполняет fn в одной транзакции: все изменения через переданные репозитории
+// сохраняются вместе или, если fn вернула ошибку, не сохраняются вовсе.
+// Турнир tournamentID заблокирован до конца транзакции: транзакции одного турнира выполняются по очереди,
+// и fn видит состояние, сохраненное предыдущей.
+type UnitOfWork interface {
+	Do(tournamentID int, fn func(tournamentRep TournamentRepository, gameRep GameRepository) error) error
 }
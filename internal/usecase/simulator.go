@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"math"
+	"math/rand"
+	"tournament/internal/entity"
+)
+
+// MatchSimulator разыгрывает одну карту (или матч, если он из одной карты) между командами.
+// Возвращает счет без ничьей; случайность берется только из переданного rng.
+type MatchSimulator interface {
+	Simulate(rng *rand.Rand, team1 entity.Team, team2 entity.Team) (int, int)
+}
+
+// UniformSimulator - обе команды выигрывают с равной вероятностью независимо от рейтинга.
+type UniformSimulator struct{}
+
+func NewUniformSimulator() *UniformSimulator {
+	return &UniformSimulator{}
+}
+
+func (s *UniformSimulator) Simulate(rng *rand.Rand, team1 entity.Team, team2 entity.Team) (int, int) {
+	return randomScore(rng, 0.5)
+}
+
+// EloSimulator - вероятность победы считается по разнице рейтингов команд, как в системе Эло:
+// при разнице в 400 очков сильная команда выигрывает примерно в 10 раз чаще.
+type EloSimulator struct{}
+
+func NewEloSimulator() *EloSimulator {
+	return &EloSimulator{}
+}
+
+func (s *EloSimulator) Simulate(rng *rand.Rand, team1 entity.Team, team2 entity.Team) (int, int) {
+	return randomScore(rng, WinProbability(team1.Rating, team2.Rating))
+}
+
+// WinProbability - ожидаемая вероятность победы команды с рейтингом rating1 над командой с rating2.
+func WinProbability(rating1 int, rating2 int) float64 {
+	return 1 / (1 + math.Pow(10, float64(rating2-rating1)/400))
+}
+
+// randomScore - случайный счет без ничьей, первая команда выигрывает с вероятностью team1WinProbability.
+func randomScore(rng *rand.Rand, team1WinProbability float64) (int, int) {
+	winnerScore := 1 + rng.Intn(3)
+	loserScore := rng.Intn(winnerScore)
+	if rng.Float64() < team1WinProbability {
+		return winnerScore, loserScore
+	}
+	return loserScore, winnerScore
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"tournament/internal/entity"
+)
+
+func TestWinProbability(t *testing.T) {
+	if p := WinProbability(1500, 1500); p != 0.5 {
+		t.Errorf("equal ratings: %v, want 0.5", p)
+	}
+	if p := WinProbability(1900, 1500); math.Abs(p-10.0/11) > 1e-9 {
+		t.Errorf("400 points ahead: %v, want %v", p, 10.0/11)
+	}
+	for _, ratings := range [][2]int{{1500, 1500}, {1800, 1200}, {1000, 2400}} {
+		if sum := WinProbability(ratings[0], ratings[1]) + WinProbability(ratings[1], ratings[0]); math.Abs(sum-1) > 1e-9 {
+			t.Errorf("ratings %v: probabilities sum to %v", ratings, sum)
+		}
+	}
+}
+
+func TestSimulators(t *testing.T) {
+	strong := entity.Team{ID: 1, Rating: 1900}
+	weak := entity.Team{ID: 2, Rating: 1500}
+	const games = 2000
+
+	for name, tc := range map[string]struct {
+		simulator MatchSimulator
+		low, high int
+	}{
+		// ожидаемо 1000 и ~1818 побед, границы с большим запасом
+		"uniform": {simulator: NewUniformSimulator(), low: 850, high: 1150},
+		"elo":     {simulator: NewEloSimulator(), low: 1700, high: 1920},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			wins := 0
+			for i := 0; i < games; i++ {
+				team1Score, team2Score := tc.simulator.Simulate(rng, strong, weak)
+				if team1Score == team2Score {
+					t.Fatalf("draw %d:%d", team1Score, team2Score)
+				}
+				if team1Score > team2Score {
+					wins++
+				}
+			}
+			if wins < tc.low || wins > tc.high {
+				t.Errorf("stronger team won %d of %d, want %d-%d", wins, games, tc.low, tc.high)
+			}
+		})
+	}
+}
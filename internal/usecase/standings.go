@@ -0,0 +1,435 @@
+package usecase
+
+import (
+	"net/http"
+	"sort"
+	"tournament/internal/entity"
+)
+
+const POINTS_FOR_WIN = 3
+const POINTS_FOR_DRAW = 1
+
+const (
+	TIEBREAK_HEAD_TO_HEAD = "head_to_head"
+	TIEBREAK_SCORE_DIFF   = "score_diff"
+	TIEBREAK_SCORES_FOR   = "scores_for"
+	TIEBREAK_WINS         = "wins"
+	TIEBREAK_RANDOM       = "random"
+)
+
+// критерии, по которым команды сравниваются до дополнительных показателей
+const RANK_BY_POINTS = "points"
+const RANK_BY_REGISTRATION = "registration"
+
+var DefaultTiebreakers = []string{TIEBREAK_HEAD_TO_HEAD, TIEBREAK_SCORE_DIFF, TIEBREAK_SCORES_FOR, TIEBREAK_RANDOM}
+
+type Standing struct {
+	Rank          int                `json:"rank"`
+	Team          entity.Team        `json:"team"`
+	Played        int                `json:"played"`
+	Won           int                `json:"won"`
+	Drawn         int                `json:"drawn"`
+	Lost          int                `json:"lost"`
+	Points        int                `json:"points"`
+	ScoresFor     int                `json:"scores_for"`
+	ScoresAgainst int                `json:"scores_against"`
+	Tiebreaks     map[string]float64 `json:"tiebreaks,omitempty"`
+	// DecidedBy - критерий, по которому команда стоит выше следующей в таблице
+	DecidedBy string `json:"decided_by,omitempty"`
+}
+
+type StandingsRequest struct {
+	Stage string `form:"stage"`
+}
+
+// StandingsResponse - таблицы стадии: по одной на группу (дивизион), для швейцарской системы - одна.
+type StandingsResponse struct {
+	StatusCode int              `json:"status_code"`
+	Stage      string           `json:"stage"`
+	Tables     []StandingsTable `json:"tables"`
+}
+
+type StandingsTable struct {
+	Group     string        `json:"group"`
+	Standings []StandingRow `json:"standings"`
+}
+
+// StandingRow - строка таблицы. Points дробные, потому что в швейцарской системе ничья - пол-очка.
+// Qualified - команда на текущий момент проходит в следующую стадию.
+type StandingRow struct {
+	Rank          int                `json:"rank"`
+	Team          entity.Team        `json:"team"`
+	Played        int                `json:"played"`
+	Won           int                `json:"won"`
+	Drawn         int                `json:"drawn"`
+	Lost          int                `json:"lost"`
+	Points        float64            `json:"points"`
+	ScoresFor     int                `json:"scores_for"`
+	ScoresAgainst int                `json:"scores_against"`
+	Tiebreaks     map[string]float64 `json:"tiebreaks"`
+	DecidedBy     string             `json:"decided_by,omitempty"`
+	Qualified     bool               `json:"qualified"`
+}
+
+// GetStandings возвращает таблицы стадии с таблицей (группы, дивизионы, швейцарская система),
+// включая команды без побед. Без stage берется первая такая стадия турнира.
+func (t *TournamentUseCase) GetStandings(tournamentID int, req StandingsRequest) (*StandingsResponse, error) {
+	tournament, err := t.getTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	state, err := t.formatState(*tournament)
+	if err != nil {
+		return nil, err
+	}
+
+	gameType, err := standingsGameType(state.Games, req.Stage)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := []StandingsTable{}
+	switch gameType {
+	case entity.GAME_TYPE_SWISS:
+		standings, err := SwissFormat{}.Standings(*state)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, StandingsTable{Standings: swissStandingRows(state.GamesByType(gameType), standings)})
+	case entity.GAME_TYPE_GROUP:
+		groups, err := ClassicFormat{}.groupStandings(*state)
+		if err != nil {
+			return nil, err
+		}
+		// в плей-офф выходят команды с мест, которые занимают сейчас, в том числе по crossover
+		seeds, err := ClassicFormat{}.playoffSeeds(*state)
+		if err != nil {
+			return nil, err
+		}
+		inPlayoff := map[int]bool{}
+		for _, team := range seeds {
+			inPlayoff[team.ID] = true
+		}
+		for i, standings := range groups {
+			tables = append(tables, StandingsTable{
+				Group: groupName(i),
+				Standings: standingRows(standings, func(standing Standing) bool {
+					return inPlayoff[standing.Team.ID]
+				}),
+			})
+		}
+	default:
+		// дивизионы старых турниров: каждый - отдельная таблица, в плей-офф выходили четыре лучших
+		standings, err := CalculateStandings(*state, state.GamesByType(gameType), tournament.Settings.Tiebreakers)
+		if err != nil {
+			return nil, err
+		}
+		group := 0
+		if gameType == entity.GAME_TYPE_DIVISION_B {
+			group = 1
+		}
+		tables = append(tables, StandingsTable{
+			Group: groupName(group),
+			Standings: standingRows(standings, func(standing Standing) bool {
+				return standing.Rank <= DEFAULT_ADVANCE_PER_GROUP
+			}),
+		})
+	}
+
+	return &StandingsResponse{
+		StatusCode: http.StatusOK,
+		Stage:      entity.GameTypeNames[gameType],
+		Tables:     tables,
+	}, nil
+}
+
+// standingsGameType - тип матчей стадии из запроса или первой стадии турнира, по которой ведется таблица.
+func standingsGameType(games []entity.Game, stage string) (int, error) {
+	if stage == "" {
+		for _, game := range games {
+			if game.IsGroupStage() {
+				return game.GameType, nil
+			}
+		}
+		return 0, NotFound(ERROR_CODE_STAGE_NOT_FOUND, "tournament has no stage with standings")
+	}
+
+	for gameType, name := range entity.GameTypeNames {
+		if name != stage {
+			continue
+		}
+		if !(entity.Game{GameType: gameType}).IsGroupStage() {
+			return 0, Validation(ERROR_CODE_UNKNOWN_STAGE, "stage %q has no standings", stage)
+		}
+		for _, game := range games {
+			if game.GameType == gameType {
+				return gameType, nil
+			}
+		}
+		return 0, NotFound(ERROR_CODE_STAGE_NOT_FOUND, "tournament has no %s stage", stage)
+	}
+	return 0, Validation(ERROR_CODE_UNKNOWN_STAGE, "unknown stage %q", stage)
+}
+
+// standingRows - строки таблицы, qualified решает, проходит ли команда дальше.
+func standingRows(standings []Standing, qualified func(standing Standing) bool) []StandingRow {
+	rows := make([]StandingRow, 0, len(standings))
+	for _, standing := range standings {
+		rows = append(rows, StandingRow{
+			Rank:          standing.Rank,
+			Team:          standing.Team,
+			Played:        standing.Played,
+			Won:           standing.Won,
+			Drawn:         standing.Drawn,
+			Lost:          standing.Lost,
+			Points:        float64(standing.Points),
+			ScoresFor:     standing.ScoresFor,
+			ScoresAgainst: standing.ScoresAgainst,
+			Tiebreaks:     standing.Tiebreaks,
+			DecidedBy:     standing.DecidedBy,
+			Qualified:     qualified(standing),
+		})
+	}
+	return rows
+}
+
+// swissStandingRows дополняет таблицу швейцарской системы статистикой матчей.
+// Пропуск (bye) считается сыгранным и выигранным матчем, как и в очках.
+func swissStandingRows(games []entity.Game, standings []SwissStanding) []StandingRow {
+	rows := make([]StandingRow, 0, len(standings))
+	for i, standing := range standings {
+		row := StandingRow{
+			Rank:   i + 1,
+			Team:   standing.Team,
+			Points: standing.Score,
+			Tiebreaks: map[string]float64{
+				"buchholz":         standing.Buchholz,
+				"sonneborn_berger": standing.SonnebornBerger,
+			},
+		}
+		for _, game := range games {
+			if !game.IsPlayed() || (game.Team1ID != standing.Team.ID && game.Team2ID != standing.Team.ID) {
+				continue
+			}
+			row.Played++
+			scoresFor, scoresAgainst := game.Scores()
+			if game.Team2ID == standing.Team.ID {
+				scoresFor, scoresAgainst = scoresAgainst, scoresFor
+			}
+			if !game.IsBye() {
+				row.ScoresFor += scoresFor
+				row.ScoresAgainst += scoresAgainst
+			}
+			switch {
+			case game.WinnerId == nil:
+				row.Drawn++
+			case *game.WinnerId == standing.Team.ID:
+				row.Won++
+			default:
+				row.Lost++
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func ValidateTiebreakers(tiebreakers []string) error {
+	used := map[string]bool{}
+	for _, tiebreaker := range tiebreakers {
+		switch tiebreaker {
+		case TIEBREAK_HEAD_TO_HEAD, TIEBREAK_SCORE_DIFF, TIEBREAK_SCORES_FOR, TIEBREAK_WINS, TIEBREAK_RANDOM:
+		default:
+			return Validation(ERROR_CODE_INVALID_SETTINGS, "unknown tiebreaker %q", tiebreaker)
+		}
+		if used[tiebreaker] {
+			return Validation(ERROR_CODE_INVALID_SETTINGS, "tiebreaker %q is used twice", tiebreaker)
+		}
+		used[tiebreaker] = true
+	}
+	return nil
+}
+
+// CalculateStandings строит таблицу по матчам группы: все команды группы, включая
+// команды без побед, сортируются по очкам, а равные - по критериям tiebreakers по порядку.
+// Жребий (random) берется из lots, записанного при формировании групп.
+// Если команды не разделил ни один критерий, выше стоит команда, раньше зарегистрированная.
+func CalculateStandings(state FormatState, games []entity.Game, tiebreakers []string) ([]Standing, error) {
+	if len(tiebreakers) == 0 {
+		tiebreakers = DefaultTiebreakers
+	}
+
+	calc := standingsCalculator{
+		games: games,
+		lots:  map[int]int{},
+		index: map[int]*Standing{},
+	}
+	for _, lot := range state.Lots {
+		calc.lots[lot.TeamID] = lot.Value
+	}
+
+	var standings []*Standing
+	for _, game := range games {
+		for _, id := range []int{game.Team1ID, game.Team2ID} {
+			if id == 0 || calc.index[id] != nil {
+				continue
+			}
+			team, err := state.Team(id)
+			if err != nil {
+				return nil, err
+			}
+			standing := &Standing{Team: team, Tiebreaks: map[string]float64{}}
+			calc.index[id] = standing
+			standings = append(standings, standing)
+		}
+	}
+
+	for _, game := range games {
+		if !game.IsPlayed() || game.IsBye() {
+			continue
+		}
+		score1, score2 := game.Scores()
+		calc.addResult(calc.index[game.Team1ID], score1, score2, game.WinnerId)
+		calc.addResult(calc.index[game.Team2ID], score2, score1, game.WinnerId)
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Points != standings[j].Points {
+			return standings[i].Points > standings[j].Points
+		}
+		return standings[i].Team.ID < standings[j].Team.ID
+	})
+	for _, tied := range splitTied(standings, func(s *Standing) float64 { return float64(s.Points) }) {
+		calc.breakTie(tied, tiebreakers)
+	}
+
+	result := make([]Standing, len(standings))
+	for i, standing := range standings {
+		standing.Rank = i + 1
+		if i+1 < len(standings) {
+			standing.DecidedBy = decidedBy(standing, standings[i+1], tiebreakers)
+		}
+		result[i] = *standing
+	}
+	return result, nil
+}
+
+type standingsCalculator struct {
+	games []entity.Game
+	lots  map[int]int
+	index map[int]*Standing
+}
+
+func (c *standingsCalculator) addResult(standing *Standing, scoresFor int, scoresAgainst int, winnerID *int) {
+	standing.Played++
+	standing.ScoresFor += scoresFor
+	standing.ScoresAgainst += scoresAgainst
+	switch {
+	case winnerID == nil:
+		standing.Drawn++
+		standing.Points += POINTS_FOR_DRAW
+	case *winnerID == standing.Team.ID:
+		standing.Won++
+		standing.Points += POINTS_FOR_WIN
+	default:
+		standing.Lost++
+	}
+}
+
+// breakTie упорядочивает команды с равными показателями по первому критерию,
+// а оставшиеся равными - по следующим.
+func (c *standingsCalculator) breakTie(tied []*Standing, tiebreakers []string) {
+	if len(tied) < 2 || len(tiebreakers) == 0 {
+		return
+	}
+
+	tiebreaker := tiebreakers[0]
+	values := c.tiebreakValues(tied, tiebreaker)
+	for _, standing := range tied {
+		standing.Tiebreaks[tiebreaker] = values[standing.Team.ID]
+	}
+
+	sort.SliceStable(tied, func(i, j int) bool {
+		// по жребию выше меньший номер, по остальным критериям - большее значение
+		if tiebreaker == TIEBREAK_RANDOM {
+			return values[tied[i].Team.ID] < values[tied[j].Team.ID]
+		}
+		return values[tied[i].Team.ID] > values[tied[j].Team.ID]
+	})
+	for _, group := range splitTied(tied, func(s *Standing) float64 { return values[s.Team.ID] }) {
+		c.breakTie(group, tiebreakers[1:])
+	}
+}
+
+// tiebreakValues - значение критерия для каждой команды.
+func (c *standingsCalculator) tiebreakValues(tied []*Standing, tiebreaker string) map[int]float64 {
+	values := map[int]float64{}
+	switch tiebreaker {
+	case TIEBREAK_HEAD_TO_HEAD:
+		// очки только в личных встречах команд, набравших одинаково
+		members := map[int]bool{}
+		for _, standing := range tied {
+			members[standing.Team.ID] = true
+			values[standing.Team.ID] = 0
+		}
+		for _, game := range c.games {
+			if !game.IsPlayed() || !members[game.Team1ID] || !members[game.Team2ID] {
+				continue
+			}
+			if game.WinnerId == nil {
+				values[game.Team1ID] += POINTS_FOR_DRAW
+				values[game.Team2ID] += POINTS_FOR_DRAW
+				continue
+			}
+			values[*game.WinnerId] += POINTS_FOR_WIN
+		}
+	case TIEBREAK_SCORE_DIFF:
+		for _, standing := range tied {
+			values[standing.Team.ID] = float64(standing.ScoresFor - standing.ScoresAgainst)
+		}
+	case TIEBREAK_SCORES_FOR:
+		for _, standing := range tied {
+			values[standing.Team.ID] = float64(standing.ScoresFor)
+		}
+	case TIEBREAK_WINS:
+		for _, standing := range tied {
+			values[standing.Team.ID] = float64(standing.Won)
+		}
+	case TIEBREAK_RANDOM:
+		for _, standing := range tied {
+			values[standing.Team.ID] = float64(c.lots[standing.Team.ID])
+		}
+	}
+	return values
+}
+
+// splitTied делит отсортированный список на группы с одинаковым значением.
+func splitTied(standings []*Standing, value func(*Standing) float64) [][]*Standing {
+	var groups [][]*Standing
+	for i := 0; i < len(standings); {
+		j := i + 1
+		for j < len(standings) && value(standings[j]) == value(standings[i]) {
+			j++
+		}
+		groups = append(groups, standings[i:j])
+		i = j
+	}
+	return groups
+}
+
+func decidedBy(a *Standing, b *Standing, tiebreakers []string) string {
+	if a.Points != b.Points {
+		return RANK_BY_POINTS
+	}
+	for _, tiebreaker := range tiebreakers {
+		valueA, okA := a.Tiebreaks[tiebreaker]
+		valueB, okB := b.Tiebreaks[tiebreaker]
+		if !okA || !okB {
+			break
+		}
+		if valueA != valueB {
+			return tiebreaker
+		}
+	}
+	return RANK_BY_REGISTRATION
+}
@@ -0,0 +1,174 @@
+package usecase_test
+
+import (
+	"testing"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+)
+
+func TestGetStandingsOfGroups(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "classic", entity.TournamentSettings{Groups: 2, AdvancePerGroup: 2}, 8)
+	started, err := uc.StartTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+	reportResult(t, uc, id, started.Games[0], 2, 1)
+
+	res, err := uc.GetStandings(id, usecase.StandingsRequest{})
+	if err != nil {
+		t.Fatalf("standings: %v", err)
+	}
+	if res.Stage != "group" || len(res.Tables) != 2 || res.Tables[0].Group != "A" || res.Tables[1].Group != "B" {
+		t.Fatalf("stage %s with tables %+v, want groups A and B", res.Stage, res.Tables)
+	}
+
+	// в таблицах все команды группы, включая еще не игравшие; проходят две лучшие
+	teams := 0
+	for _, table := range res.Tables {
+		if len(table.Standings) != 4 {
+			t.Errorf("group %s has %d teams, want 4", table.Group, len(table.Standings))
+		}
+		for i, row := range table.Standings {
+			teams++
+			if row.Rank != i+1 || row.Qualified != (i < 2) {
+				t.Errorf("group %s row %d: rank %d, qualified %v", table.Group, i, row.Rank, row.Qualified)
+			}
+		}
+	}
+	if teams != 8 {
+		t.Errorf("%d teams in tables, want 8", teams)
+	}
+
+	winner := started.Games[0].Team1ID
+	for _, table := range res.Tables {
+		for _, row := range table.Standings {
+			if row.Team.ID == winner && (row.Rank != 1 || row.Points != usecase.POINTS_FOR_WIN || row.ScoresFor != 2 || row.ScoresAgainst != 1) {
+				t.Errorf("winner of the first game %+v", row)
+			}
+		}
+	}
+}
+
+func TestGetStandingsOfSwiss(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "swiss", entity.TournamentSettings{}, 5)
+	started, err := uc.StartTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+	// первый тур не доигран, чтобы второй не был создан
+	var bye, game entity.Game
+	for _, g := range started.Games {
+		if g.IsBye() {
+			bye = g
+		} else if game.ID == 0 {
+			game = g
+		}
+	}
+	reportResult(t, uc, id, game, 1, 0)
+
+	res, err := uc.GetStandings(id, usecase.StandingsRequest{Stage: "swiss"})
+	if err != nil {
+		t.Fatalf("standings: %v", err)
+	}
+	if res.Stage != "swiss" || len(res.Tables) != 1 || len(res.Tables[0].Standings) != 5 {
+		t.Fatalf("stage %s with tables %+v, want one swiss table of 5 teams", res.Stage, res.Tables)
+	}
+
+	// пропуск считается сыгранным и выигранным матчем
+	played := map[int]int{bye.Team1ID: 1, game.Team1ID: 1, game.Team2ID: 1}
+	points := map[int]float64{bye.Team1ID: 1, game.Team1ID: 1}
+	for _, row := range res.Tables[0].Standings {
+		if _, ok := row.Tiebreaks["buchholz"]; !ok {
+			t.Errorf("team %d has no buchholz", row.Team.ID)
+		}
+		if _, ok := row.Tiebreaks["sonneborn_berger"]; !ok {
+			t.Errorf("team %d has no sonneborn-berger", row.Team.ID)
+		}
+		if row.Played != played[row.Team.ID] || row.Points != points[row.Team.ID] {
+			t.Errorf("team %d played %d with %v points, want %d with %v", row.Team.ID, row.Played, row.Points, played[row.Team.ID], points[row.Team.ID])
+		}
+	}
+	if top := res.Tables[0].Standings[0].Team.ID; top != bye.Team1ID && top != game.Team1ID {
+		t.Errorf("team %d leads without a win", top)
+	}
+}
+
+func TestGetStandingsErrors(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "classic", entity.TournamentSettings{Groups: 2, AdvancePerGroup: 2}, 8)
+	if _, err := uc.GetStandings(id, usecase.StandingsRequest{}); errorCode(err) != usecase.ERROR_CODE_STAGE_NOT_FOUND {
+		t.Errorf("standings before the group stage: error %v, want %s", err, usecase.ERROR_CODE_STAGE_NOT_FOUND)
+	}
+	if _, err := uc.StartTournament(id, usecase.RunTournamentRequest{}); err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+	for stage, code := range map[string]string{
+		"final":      usecase.ERROR_CODE_UNKNOWN_STAGE,
+		"swiss":      usecase.ERROR_CODE_STAGE_NOT_FOUND,
+		"round_of_3": usecase.ERROR_CODE_UNKNOWN_STAGE,
+	} {
+		if _, err := uc.GetStandings(id, usecase.StandingsRequest{Stage: stage}); errorCode(err) != code {
+			t.Errorf("standings of %s: error %v, want %s", stage, err, code)
+		}
+	}
+
+	id, _ = createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 4)
+	if _, err := uc.RunTournament(id, usecase.RunTournamentRequest{}); err != nil {
+		t.Fatalf("run tournament: %v", err)
+	}
+	if _, err := uc.GetStandings(id, usecase.StandingsRequest{}); err == nil {
+		t.Error("standings of single elimination: no error")
+	}
+}
+
+func TestGetStandingsQualifiesPlayoffTeams(t *testing.T) {
+	uc := newUseCase()
+	settings := entity.TournamentSettings{Groups: 2, AdvancePerGroup: 2, Crossover: []string{"A1-B2", "B1-A2"}}
+	id, teams := createTournament(t, uc, "classic", settings, 9)
+	started, err := uc.StartTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+	for _, game := range started.Games {
+		reportResult(t, uc, id, game, 2, 1)
+	}
+
+	// после последнего матча групп создается первый раунд плей-офф
+	games, err := uc.ListGames(id, usecase.ListGamesRequest{})
+	if err != nil {
+		t.Fatalf("games: %v", err)
+	}
+	inPlayoff := map[int]bool{}
+	for _, game := range games.Games[len(started.Games):] {
+		inPlayoff[game.Team1ID] = true
+		inPlayoff[game.Team2ID] = true
+	}
+	if len(inPlayoff) != 4 {
+		t.Fatalf("%d teams in playoff, want 4", len(inPlayoff))
+	}
+
+	res, err := uc.GetStandings(id, usecase.StandingsRequest{Stage: "group"})
+	if err != nil {
+		t.Fatalf("standings: %v", err)
+	}
+	for _, table := range res.Tables {
+		for _, row := range table.Standings {
+			if row.Qualified != inPlayoff[row.Team.ID] {
+				t.Errorf("group %s team %d: qualified %v, in playoff %v", table.Group, row.Team.ID, row.Qualified, inPlayoff[row.Team.ID])
+			}
+		}
+	}
+
+	// доигрываем плей-офф: места получают и команды плей-офф, и все оставшиеся в группах
+	result, err := uc.RunTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	placements := make([]entity.Placement, 0, len(result.Placements))
+	for _, placement := range result.Placements {
+		placements = append(placements, entity.Placement{TeamID: placement.Team.ID, PlaceFrom: placement.PlaceFrom, PlaceTo: placement.PlaceTo})
+	}
+	checkPlacements(t, placements, teams)
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"testing"
+	"tournament/internal/entity"
+)
+
+// playedGame - сыгранный матч группы со счетом team1Score:team2Score.
+func playedGame(t *testing.T, team1 int, team2 int, team1Score int, team2Score int) entity.Game {
+	t.Helper()
+	game := entity.Game{Team1ID: team1, Team2ID: team2, GameType: entity.GAME_TYPE_GROUP}
+	if err := setGameResult(&game, team1Score, team2Score, nil); err != nil {
+		t.Fatalf("game %d vs %d: %v", team1, team2, err)
+	}
+	return game
+}
+
+func TestCalculateStandingsTiebreakers(t *testing.T) {
+	// 2 и 1 набрали поровну, 2 выиграла личную встречу
+	pair := []entity.Game{playedGame(t, 2, 1, 1, 0), playedGame(t, 1, 3, 1, 0)}
+	// круг: каждая команда по разу выиграла и проиграла с одинаковым счетом
+	cycle := []entity.Game{playedGame(t, 1, 2, 1, 0), playedGame(t, 2, 3, 1, 0), playedGame(t, 3, 1, 1, 0)}
+	// у 1 и 2 по 3 очка: 1 выиграла личную встречу, у 2 лучше разница
+	margin := []entity.Game{playedGame(t, 1, 2, 1, 0), playedGame(t, 1, 3, 0, 3), playedGame(t, 2, 4, 5, 0), playedGame(t, 3, 4, 2, 0)}
+	// у всех по очку за ничьи, выше больше забившие
+	draws := []entity.Game{playedGame(t, 1, 2, 1, 1), playedGame(t, 3, 4, 2, 2)}
+
+	cases := []struct {
+		name        string
+		games       []entity.Game
+		tiebreakers []string
+		lots        []entity.Lot
+		order       []int
+		decidedBy   []string
+	}{
+		{
+			name:      "head to head",
+			games:     pair,
+			order:     []int{2, 1, 3},
+			decidedBy: []string{TIEBREAK_HEAD_TO_HEAD, RANK_BY_POINTS, ""},
+		},
+		{
+			name:        "head to head before score difference",
+			games:       margin,
+			tiebreakers: []string{TIEBREAK_HEAD_TO_HEAD, TIEBREAK_SCORE_DIFF},
+			order:       []int{3, 1, 2, 4},
+			decidedBy:   []string{RANK_BY_POINTS, TIEBREAK_HEAD_TO_HEAD, RANK_BY_POINTS, ""},
+		},
+		{
+			name:        "score difference before head to head",
+			games:       margin,
+			tiebreakers: []string{TIEBREAK_SCORE_DIFF, TIEBREAK_HEAD_TO_HEAD},
+			order:       []int{3, 2, 1, 4},
+			decidedBy:   []string{RANK_BY_POINTS, TIEBREAK_SCORE_DIFF, RANK_BY_POINTS, ""},
+		},
+		{
+			name:        "draws and scores for",
+			games:       draws,
+			tiebreakers: []string{TIEBREAK_SCORE_DIFF, TIEBREAK_SCORES_FOR},
+			order:       []int{3, 4, 1, 2},
+			decidedBy:   []string{RANK_BY_REGISTRATION, TIEBREAK_SCORES_FOR, RANK_BY_REGISTRATION, ""},
+		},
+		{
+			name:      "lots break a full tie",
+			games:     cycle,
+			lots:      []entity.Lot{{TeamID: 1, Value: 3}, {TeamID: 2, Value: 1}, {TeamID: 3, Value: 2}},
+			order:     []int{2, 3, 1},
+			decidedBy: []string{TIEBREAK_RANDOM, TIEBREAK_RANDOM, ""},
+		},
+		{
+			name:        "registration order without a deciding tiebreaker",
+			games:       cycle,
+			tiebreakers: []string{TIEBREAK_HEAD_TO_HEAD, TIEBREAK_WINS},
+			order:       []int{1, 2, 3},
+			decidedBy:   []string{RANK_BY_REGISTRATION, RANK_BY_REGISTRATION, ""},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := FormatState{Teams: newTeams(4), Lots: tc.lots}
+			standings, err := CalculateStandings(state, tc.games, tc.tiebreakers)
+			if err != nil {
+				t.Fatalf("calculate standings: %v", err)
+			}
+			if len(standings) != len(tc.order) {
+				t.Fatalf("%d rows, want %d", len(standings), len(tc.order))
+			}
+			for i, standing := range standings {
+				if standing.Rank != i+1 || standing.Team.ID != tc.order[i] {
+					t.Errorf("rank %d is team %d, want team %d", standing.Rank, standing.Team.ID, tc.order[i])
+				}
+				if standing.DecidedBy != tc.decidedBy[i] {
+					t.Errorf("rank %d decided by %q, want %q", standing.Rank, standing.DecidedBy, tc.decidedBy[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTiebreakers(t *testing.T) {
+	if err := ValidateTiebreakers(DefaultTiebreakers); err != nil {
+		t.Errorf("default tiebreakers: %v", err)
+	}
+	for _, tiebreakers := range [][]string{{"coin_flip"}, {TIEBREAK_WINS, TIEBREAK_WINS}} {
+		if err := ValidateTiebreakers(tiebreakers); err == nil {
+			t.Errorf("tiebreakers %v: no error", tiebreakers)
+		}
+	}
+}
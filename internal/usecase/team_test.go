@@ -0,0 +1,101 @@
+package usecase_test
+
+import (
+	"strings"
+	"testing"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+)
+
+func TestAddTeamValidatesName(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 0)
+
+	res, err := uc.AddTeam(id, usecase.AddTeamRequest{Name: "  Natus Vincere  "})
+	if err != nil {
+		t.Fatalf("add team: %v", err)
+	}
+	if res.Team.Name != "Natus Vincere" {
+		t.Errorf("name %q, want it trimmed", res.Team.Name)
+	}
+
+	// длина считается в символах, а не в байтах
+	if _, err := uc.AddTeam(id, usecase.AddTeamRequest{Name: strings.Repeat("я", usecase.TEAM_NAME_MAX_LENGTH)}); err != nil {
+		t.Errorf("name of %d cyrillic letters: %v", usecase.TEAM_NAME_MAX_LENGTH, err)
+	}
+
+	cases := map[string]string{
+		"too short":             "X",
+		"too short after trim":  "  X  ",
+		"too long":              strings.Repeat("я", usecase.TEAM_NAME_MAX_LENGTH+1),
+		"only spaces":           "      ",
+		"taken":                 "Natus Vincere",
+		"taken in another case": "NATUS VINCERE",
+	}
+	for name, teamName := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := uc.AddTeam(id, usecase.AddTeamRequest{Name: teamName})
+			code := usecase.ERROR_CODE_INVALID_TEAM_NAME
+			if strings.HasPrefix(name, "taken") {
+				code = usecase.ERROR_CODE_TEAM_NAME_TAKEN
+			}
+			if errorCode(err) != code {
+				t.Errorf("error %v, want %s", err, code)
+			}
+		})
+	}
+
+	// в другом турнире название свободно
+	otherID, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 0)
+	if _, err := uc.AddTeam(otherID, usecase.AddTeamRequest{Name: "Natus Vincere"}); err != nil {
+		t.Errorf("same name in another tournament: %v", err)
+	}
+}
+
+func TestUpdateTeamValidatesName(t *testing.T) {
+	uc := newUseCase()
+	id, teams := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 2)
+
+	// смена регистра собственного названия - не конфликт
+	name := strings.ToUpper(teams[0].Name)
+	if _, err := uc.UpdateTeam(id, teams[0].ID, usecase.UpdateTeamRequest{Name: &name}); err != nil {
+		t.Errorf("rename to own name in upper case: %v", err)
+	}
+
+	name = strings.ToLower(teams[1].Name)
+	if _, err := uc.UpdateTeam(id, teams[0].ID, usecase.UpdateTeamRequest{Name: &name}); errorCode(err) != usecase.ERROR_CODE_TEAM_NAME_TAKEN {
+		t.Errorf("rename to a taken name: error %v, want %s", err, usecase.ERROR_CODE_TEAM_NAME_TAKEN)
+	}
+	name = " "
+	if _, err := uc.UpdateTeam(id, teams[0].ID, usecase.UpdateTeamRequest{Name: &name}); errorCode(err) != usecase.ERROR_CODE_INVALID_TEAM_NAME {
+		t.Errorf("rename to a blank name: error %v, want %s", err, usecase.ERROR_CODE_INVALID_TEAM_NAME)
+	}
+}
+
+func TestRosterCap(t *testing.T) {
+	uc := newUseCase()
+	settings := entity.TournamentSettings{Groups: 2, GroupSize: 2, AdvancePerGroup: 1}
+	id, _ := createTournament(t, uc, "classic", settings, 4)
+
+	_, err := uc.AddTeam(id, usecase.AddTeamRequest{Name: "Fifth"})
+	if errorCode(err) != usecase.ERROR_CODE_TOURNAMENT_FULL {
+		t.Errorf("fifth team in 2 groups of 2: error %v, want %s", err, usecase.ERROR_CODE_TOURNAMENT_FULL)
+	}
+
+	// настройки, в которые не помещаются уже зарегистрированные команды, не принимаются
+	if _, err := uc.UpdateTournament(id, usecase.UpdateTournamentRequest{Settings: &entity.TournamentSettings{Groups: 1, GroupSize: 3}}); errorCode(err) != usecase.ERROR_CODE_TOURNAMENT_FULL {
+		t.Errorf("shrink to one group of 3: error %v, want %s", err, usecase.ERROR_CODE_TOURNAMENT_FULL)
+	}
+	if _, err := uc.UpdateTournament(id, usecase.UpdateTournamentRequest{Settings: &entity.TournamentSettings{Groups: 2, GroupSize: 3, AdvancePerGroup: 1}}); err != nil {
+		t.Errorf("grow to 2 groups of 3: %v", err)
+	}
+	if _, err := uc.AddTeam(id, usecase.AddTeamRequest{Name: "Fifth"}); err != nil {
+		t.Errorf("fifth team in 2 groups of 3: %v", err)
+	}
+
+	// в швейцарской системе из трех раундов не больше восьми команд
+	id, _ = createTournament(t, uc, "swiss", entity.TournamentSettings{SwissRounds: 3}, 8)
+	if _, err := uc.AddTeam(id, usecase.AddTeamRequest{Name: "Ninth"}); errorCode(err) != usecase.ERROR_CODE_TOURNAMENT_FULL {
+		t.Errorf("ninth team in 3 swiss rounds: error %v, want %s", err, usecase.ERROR_CODE_TOURNAMENT_FULL)
+	}
+}
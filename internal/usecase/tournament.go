@@ -1,10 +1,15 @@
 package usecase
 
 import (
+	"cmp"
+	"database/sql"
 	"errors"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 	"tournament/internal/entity"
+	"unicode/utf8"
 
 	"math/rand"
 )
@@ -12,17 +17,23 @@ import (
 type TournamentUseCase struct {
 	TournamentRepository TournamentRepository
 	GameRepository       GameRepository
+	UnitOfWork           UnitOfWork
+	MatchSimulator       MatchSimulator
 }
 
-func NewTournamentUsecase(tournamentRep TournamentRepository, gameRep GameRepository) *TournamentUseCase {
+func NewTournamentUsecase(tournamentRep TournamentRepository, gameRep GameRepository, uow UnitOfWork, simulator MatchSimulator) *TournamentUseCase {
 	return &TournamentUseCase{
 		TournamentRepository: tournamentRep,
 		GameRepository:       gameRep,
+		UnitOfWork:           uow,
+		MatchSimulator:       simulator,
 	}
 }
 
 type CreateTournamentRequest struct {
-	Name string `json:"name" binding:"required"`
+	Name     string                    `json:"name" binding:"required"`
+	Format   string                    `json:"format"`
+	Settings entity.TournamentSettings `json:"settings"`
 }
 
 type CreateTournamentResponse struct {
@@ -35,8 +46,16 @@ type DeleteTournamentResponse struct {
 	Tournament *entity.Tournament `json:"tournament"`
 }
 
+// ограничения длины названия команды в символах, пробелы по краям не считаются
+const TEAM_NAME_MIN_LENGTH = 2
+const TEAM_NAME_MAX_LENGTH = 64
+
 type AddTeamRequest struct {
-	Name string `json:"name" binding:"required"`
+	Name   string `json:"name" binding:"required"`
+	Rating int    `json:"rating" binding:"omitempty,min=0"`
+	// Seed - место в посеве, 0 - после посеянных команд в порядке регистрации
+	Seed   int    `json:"seed" binding:"omitempty,min=0"`
+	Region string `json:"region" binding:"max=64"`
 }
 
 type AddTeamResponse struct {
@@ -44,15 +63,109 @@ type AddTeamResponse struct {
 	Team       *entity.Team `json:"team"`
 }
 
+// UpdateTournamentRequest - изменяются только переданные поля. Settings заменяются целиком.
+type UpdateTournamentRequest struct {
+	Name     *string                    `json:"name" binding:"omitempty,min=1"`
+	Format   *string                    `json:"format"`
+	Settings *entity.TournamentSettings `json:"settings"`
+}
+
+type UpdateTournamentResponse struct {
+	StatusCode int                `json:"status_code"`
+	Tournament *entity.Tournament `json:"tournament"`
+}
+
+// UpdateTeamRequest - изменяются только переданные поля.
+type UpdateTeamRequest struct {
+	Name   *string `json:"name"`
+	Rating *int    `json:"rating" binding:"omitempty,min=0"`
+	Seed   *int    `json:"seed" binding:"omitempty,min=0"`
+	Region *string `json:"region" binding:"omitempty,max=64"`
+}
+
+type UpdateTeamResponse struct {
+	StatusCode int          `json:"status_code"`
+	Team       *entity.Team `json:"team"`
+}
+
+type DeleteTeamResponse struct {
+	StatusCode int `json:"status_code"`
+}
+
+// RunTournamentRequest - параметры запуска. Если Seed не задан, берется сохраненный
+// при прошлом запуске, а для нового турнира - случайный; использованный сид сохраняется.
+type RunTournamentRequest struct {
+	Seed *int64 `json:"seed"`
+}
+
+type AdvanceTournamentResponse struct {
+	StatusCode int                `json:"status_code"`
+	Tournament *entity.Tournament `json:"tournament"`
+	// Games - матчи, созданные на этом шаге
+	Games []entity.Game `json:"games"`
+}
+
+// ReportGameResultRequest - результат матча: счет или карты серии. WinnerID необязателен.
+type ReportGameResultRequest struct {
+	Team1Score *int             `json:"team1_score" binding:"omitempty,min=0"`
+	Team2Score *int             `json:"team2_score" binding:"omitempty,min=0"`
+	Maps       []entity.GameMap `json:"maps"`
+	WinnerID   *int             `json:"winner_id"`
+}
+
+type ReportGameResultResponse struct {
+	StatusCode int          `json:"status_code"`
+	Game       *entity.Game `json:"game"`
+	// NextGames - матчи, созданные после этого результата
+	NextGames  []entity.Game      `json:"next_games"`
+	Tournament *entity.Tournament `json:"tournament"`
+}
+
+type ResumeTournamentResponse struct {
+	StatusCode int                `json:"status_code"`
+	Tournament *entity.Tournament `json:"tournament"`
+	// LastCompletedStage - номер последней стадии, все матчи которой и всех предыдущих решены
+	LastCompletedStage int `json:"last_completed_stage"`
+	// Games - матчи, созданные при возобновлении
+	Games []entity.Game `json:"games"`
+}
+
 type TournamentResultResponse struct {
-	StatusCode int         `json:"status_code"`
-	Winner     entity.Team `json:"winner"`
+	StatusCode int                 `json:"status_code"`
+	Seed       int64               `json:"seed"`
+	Winner     entity.Team         `json:"winner"`
+	Podium     []entity.Team       `json:"podium"`
+	Placements []PlacementResponse `json:"placements"`
+	Games      []entity.Game       `json:"games"`
+}
+
+type PlacementResponse struct {
+	PlaceFrom int         `json:"place_from"`
+	PlaceTo   int         `json:"place_to"`
+	Team      entity.Team `json:"team"`
 }
 
 func (t *TournamentUseCase) CreateTournament(req CreateTournamentRequest) (*CreateTournamentResponse, error) {
 
+	if req.Format == "" {
+		req.Format = DEFAULT_FORMAT
+	}
+
+	format, err := GetFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateSettings(format, req.Settings)
+	if err != nil {
+		return nil, err
+	}
+
 	tournament := entity.Tournament{
-		Name: req.Name,
+		Name:     req.Name,
+		Format:   req.Format,
+		Status:   entity.TOURNAMENT_STATUS_REGISTRATION,
+		Settings: req.Settings,
 	}
 
 	res, err := t.TournamentRepository.Create(tournament)
@@ -68,7 +181,7 @@ func (t *TournamentUseCase) CreateTournament(req CreateTournamentRequest) (*Crea
 }
 
 func (t *TournamentUseCase) DeleteTournament(tournamentID int) (*DeleteTournamentResponse, error) {
-	tournament, err := t.TournamentRepository.GetById(tournamentID)
+	tournament, err := t.getTournament(tournamentID)
 
 	if err != nil {
 		return nil, err
@@ -86,20 +199,36 @@ func (t *TournamentUseCase) DeleteTournament(tournamentID int) (*DeleteTournamen
 }
 
 func (t *TournamentUseCase) AddTeam(tournamentID int, req AddTeamRequest) (*AddTeamResponse, error) {
-	tournament, err := t.TournamentRepository.GetById(tournamentID)
+	tournament, err := t.getTournament(tournamentID)
 
 	if err != nil {
 		return nil, err
 	}
 
-	team := entity.Team{
-		Name: req.Name,
+	if tournament.Status != entity.TOURNAMENT_STATUS_REGISTRATION {
+		return nil, InvalidState(ERROR_CODE_REGISTRATION_CLOSED, "registration is closed, tournament is in %s", tournament.Status)
+	}
+
+	teams, err := t.TournamentRepository.GetTeams(tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRoster(*tournament, len(teams)+1); err != nil {
+		return nil, err
+	}
+
+	team := newTeam(tournament.ID, req)
+	if err := checkTeamName(&team, teams); err != nil {
+		return nil, err
+	}
+	if err := checkTeamSeed(team, teams); err != nil {
+		return nil, err
 	}
 
 	res, err := t.TournamentRepository.AddTeam(tournament.ID, team)
 
 	if err != nil {
-		return nil, err
+		return nil, teamNameTaken(err, team)
 	}
 
 	return &AddTeamResponse{
@@ -108,153 +237,692 @@ func (t *TournamentUseCase) AddTeam(tournamentID int, req AddTeamRequest) (*AddT
 	}, nil
 }
 
-func (t *TournamentUseCase) GenerateDivisionSchedule(tournamentId int) error {
-	teams, err := t.TournamentRepository.GetTeams(tournamentId)
+// UpdateTournament переименовывает турнир; формат и настройки можно менять только до старта.
+func (t *TournamentUseCase) UpdateTournament(tournamentID int, req UpdateTournamentRequest) (*UpdateTournamentResponse, error) {
+	tournament, err := t.getTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		tournament.Name = *req.Name
+	}
+
+	if req.Format != nil || req.Settings != nil {
+		if tournament.Status != entity.TOURNAMENT_STATUS_REGISTRATION && tournament.Status != entity.TOURNAMENT_STATUS_SEEDING {
+			return nil, InvalidState(ERROR_CODE_TOURNAMENT_STARTED, "format and settings can only be changed before start, tournament is in %s", tournament.Status)
+		}
+		if req.Format != nil {
+			tournament.Format = *req.Format
+		}
+		if req.Settings != nil {
+			tournament.Settings = *req.Settings
+		}
+
+		format, err := GetFormat(tournament.Format)
+		if err != nil {
+			return nil, err
+		}
+		// старые настройки могут не подходить новому формату, поэтому проверяются вместе
+		if err := validateSettings(format, tournament.Settings); err != nil {
+			return nil, err
+		}
+
+		teams, err := t.TournamentRepository.GetTeams(tournament.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkRoster(*tournament, len(teams)); err != nil {
+			return nil, err
+		}
+		if tournament.Status == entity.TOURNAMENT_STATUS_SEEDING {
+			if err := t.checkFirstStage(*tournament); err != nil {
+				return nil, err
+			}
+		}
+	}
 
+	res, err := t.TournamentRepository.Update(*tournament)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	//разделения на два дивизиона
-	firstDivision, secondDivision, err := splitToDivisions(teams)
+	return &UpdateTournamentResponse{
+		StatusCode: http.StatusOK,
+		Tournament: res,
+	}, nil
+}
 
+// UpdateTeam переименовывает команду; рейтинг влияет на посев и меняется только во время регистрации.
+func (t *TournamentUseCase) UpdateTeam(tournamentID int, teamID int, req UpdateTeamRequest) (*UpdateTeamResponse, error) {
+	tournament, team, err := t.tournamentTeam(tournamentID, teamID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	//генерация расписания для первого дивизиона
-	for i := 0; i < len(firstDivision); i++ {
-		for j := i + 1; j < len(firstDivision); j++ {
-			_, err := t.GameRepository.Create(tournamentId, firstDivision[i], firstDivision[j], entity.GAME_TYPE_DIVISION_A)
-			if err != nil {
-				return err
-			}
-		}
+
+	teams, err := t.TournamentRepository.GetTeams(tournament.ID)
+	if err != nil {
+		return nil, err
 	}
+	// остальные команды турнира, с которыми не должны совпадать название и место в посеве
+	others := slices.DeleteFunc(teams, func(other entity.Team) bool {
+		return other.ID == team.ID
+	})
 
-	//генерация расписания для второго дивизиона
-	for i := 0; i < len(secondDivision); i++ {
-		for j := i + 1; j < len(secondDivision); j++ {
-			_, err := t.GameRepository.Create(tournamentId, secondDivision[i], secondDivision[j], entity.GAME_TYPE_DIVISION_B)
-			if err != nil {
-				return err
-			}
+	if req.Name != nil {
+		team.Name = *req.Name
+		if err := checkTeamName(team, others); err != nil {
+			return nil, err
+		}
+	}
+	if req.Rating != nil {
+		if tournament.Status != entity.TOURNAMENT_STATUS_REGISTRATION {
+			return nil, InvalidState(ERROR_CODE_REGISTRATION_CLOSED, "rating can only be changed during registration, tournament is in %s", tournament.Status)
+		}
+		team.Rating = *req.Rating
+		if team.Rating == 0 {
+			team.Rating = entity.DEFAULT_RATING
+		}
+	}
+	if req.Seed != nil {
+		if tournament.Status != entity.TOURNAMENT_STATUS_REGISTRATION {
+			return nil, InvalidState(ERROR_CODE_REGISTRATION_CLOSED, "seed can only be changed during registration, tournament is in %s", tournament.Status)
+		}
+		team.Seed = *req.Seed
+		if err := checkTeamSeed(*team, others); err != nil {
+			return nil, err
 		}
 	}
+	if req.Region != nil {
+		team.Region = *req.Region
+	}
 
-	return nil
+	res, err := t.TournamentRepository.UpdateTeam(*team)
+	if err != nil {
+		return nil, teamNameTaken(err, *team)
+	}
+
+	return &UpdateTeamResponse{
+		StatusCode: http.StatusOK,
+		Team:       res,
+	}, nil
 }
 
-func (t *TournamentUseCase) GenerateDivisionResult(tournamentID int) error {
-	err := t.GenerateResultByGameType(tournamentID, entity.GAME_TYPE_DIVISION_A)
+func (t *TournamentUseCase) DeleteTeam(tournamentID int, teamID int) (*DeleteTeamResponse, error) {
+	tournament, team, err := t.tournamentTeam(tournamentID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tournament.Status != entity.TOURNAMENT_STATUS_REGISTRATION {
+		return nil, InvalidState(ERROR_CODE_REGISTRATION_CLOSED, "registration is closed, tournament is in %s", tournament.Status)
+	}
 
+	err = t.TournamentRepository.DeleteTeam(*team)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = t.GenerateResultByGameType(tournamentID, entity.GAME_TYPE_DIVISION_B)
+	return &DeleteTeamResponse{
+		StatusCode: http.StatusOK,
+	}, nil
+}
 
+// tournamentTeam - турнир и его команда; команда другого турнира считается не найденной.
+func (t *TournamentUseCase) tournamentTeam(tournamentID int, teamID int) (*entity.Tournament, *entity.Team, error) {
+	tournament, err := t.getTournament(tournamentID)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+
+	team, err := t.TournamentRepository.GetTeam(teamID)
+	if err != nil {
+		return nil, nil, notFound(err, ERROR_CODE_TEAM_NOT_FOUND, "team %d is not registered in tournament %d", teamID, tournamentID)
+	}
+	if team.TournamentID != tournament.ID {
+		return nil, nil, NotFound(ERROR_CODE_TEAM_NOT_FOUND, "team %d is not registered in tournament %d", teamID, tournamentID)
+	}
+	return tournament, team, nil
+}
+
+// newTeam - команда из заявки, без рейтинга команда получает DEFAULT_RATING.
+func newTeam(tournamentID int, req AddTeamRequest) entity.Team {
+	team := entity.Team{
+		TournamentID: tournamentID,
+		Name:         req.Name,
+		Rating:       req.Rating,
+		Seed:         req.Seed,
+		Region:       strings.TrimSpace(req.Region),
+	}
+	if team.Rating == 0 {
+		team.Rating = entity.DEFAULT_RATING
+	}
+	return team
+}
+
+// checkTeamName убирает пробелы по краям названия команды и проверяет его длину и уникальность
+// в турнире без учета регистра. others - остальные команды турнира.
+func checkTeamName(team *entity.Team, others []entity.Team) error {
+	team.Name = strings.TrimSpace(team.Name)
+	length := utf8.RuneCountInString(team.Name)
+	if length < TEAM_NAME_MIN_LENGTH || length > TEAM_NAME_MAX_LENGTH {
+		return Validation(ERROR_CODE_INVALID_TEAM_NAME, "team name must be between %d and %d characters", TEAM_NAME_MIN_LENGTH, TEAM_NAME_MAX_LENGTH)
 	}
+	for _, other := range others {
+		if strings.EqualFold(other.Name, team.Name) {
+			return teamNameTaken(ErrDuplicate, *team)
+		}
+	}
+	return nil
+}
 
+// checkTeamSeed проверяет, что место в посеве не занято другой командой турнира.
+func checkTeamSeed(team entity.Team, others []entity.Team) error {
+	if team.Seed == 0 {
+		return nil
+	}
+	for _, other := range others {
+		if other.Seed == team.Seed {
+			return Conflict(ERROR_CODE_TEAM_SEED_TAKEN, "seed %d is already taken by team %q", team.Seed, other.Name)
+		}
+	}
 	return nil
 }
 
-func (t *TournamentUseCase) GeneratePlayoffStage1Schedule(tournamentId int) error {
-	//генерация расписания для первой стадии плейофф
+// teamNameTaken заменяет ErrDuplicate репозитория на конфликт: проверку в checkTeamName
+// может опередить параллельный запрос с тем же названием.
+func teamNameTaken(err error, team entity.Team) error {
+	if errors.Is(err, ErrDuplicate) {
+		return Conflict(ERROR_CODE_TEAM_NAME_TAKEN, "team %q is already registered in tournament %d", team.Name, team.TournamentID)
+	}
+	return err
+}
 
-	//берем топ 4 команды с каждого дивизиона и формируем расписание для первой стадии плей офф
-	firstDivisionWinnners, err := t.GameRepository.GetTop4WinnersByType(tournamentId, entity.GAME_TYPE_DIVISION_A)
+// checkRoster проверяет, что teamCount команд помещается в формат турнира.
+func checkRoster(tournament entity.Tournament, teamCount int) error {
+	format, err := GetFormat(tournament.Format)
 	if err != nil {
 		return err
 	}
-	secondDivisionWinners, err := t.GameRepository.GetTop4WinnersByType(tournamentId, entity.GAME_TYPE_DIVISION_B)
+	if maxTeams := format.MaxTeams(tournament.Settings); teamCount > maxTeams {
+		return Conflict(ERROR_CODE_TOURNAMENT_FULL, "tournament %s format allows at most %d teams, got %d", tournament.Format, maxTeams, teamCount)
+	}
+	return nil
+}
+
+// StartTournament закрывает регистрацию и создает матчи первой стадии, результаты которых вносятся через ReportGameResult.
+func (t *TournamentUseCase) StartTournament(tournamentID int, req RunTournamentRequest) (*AdvanceTournamentResponse, error) {
+	tournament, err := t.getTournament(tournamentID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if tournament.Status != entity.TOURNAMENT_STATUS_REGISTRATION && tournament.Status != entity.TOURNAMENT_STATUS_SEEDING {
+		return nil, InvalidState(ERROR_CODE_TOURNAMENT_STARTED, "tournament has already started, it is in %s", tournament.Status)
 	}
 
-	//лучшие играют с худшими с другого дивизиона
-	_, err = t.GameRepository.Create(tournamentId, firstDivisionWinnners[0], secondDivisionWinners[3], entity.GAME_TYPE_PLAYOFF_STAGE_1)
+	var games []entity.Game
+	for !inProgress(*tournament) && tournament.Status != entity.TOURNAMENT_STATUS_FINISHED {
+		var created []entity.Game
+		tournament, created, err = t.advanceStep(tournament.ID, req.Seed, false)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, created...)
+	}
+
+	return &AdvanceTournamentResponse{
+		StatusCode: http.StatusOK,
+		Tournament: tournament,
+		Games:      games,
+	}, nil
+}
+
+// AdvanceTournament переводит турнир на следующий шаг жизненного цикла (см. advance).
+// Матчи не симулируются: пока у текущей стадии есть несыгранные матчи, шаг отклоняется, доиграть их может RunTournament.
+func (t *TournamentUseCase) AdvanceTournament(tournamentID int, req RunTournamentRequest) (*AdvanceTournamentResponse, error) {
+	tournament, err := t.getTournament(tournamentID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	_, err = t.GameRepository.Create(tournamentId, secondDivisionWinners[0], firstDivisionWinnners[3], entity.GAME_TYPE_PLAYOFF_STAGE_1)
+
+	tournament, games, err := t.advanceStep(tournament.ID, req.Seed, false)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	_, err = t.GameRepository.Create(tournamentId, firstDivisionWinnners[1], secondDivisionWinners[2], entity.GAME_TYPE_PLAYOFF_STAGE_1)
+
+	return &AdvanceTournamentResponse{
+		StatusCode: http.StatusOK,
+		Tournament: tournament,
+		Games:      games,
+	}, nil
+}
+
+// CancelTournament отменяет незавершенный турнир; сыгранные матчи сохраняются.
+func (t *TournamentUseCase) CancelTournament(tournamentID int) (*AdvanceTournamentResponse, error) {
+	tournament, err := t.getTournament(tournamentID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	_, err = t.GameRepository.Create(tournamentId, secondDivisionWinners[1], firstDivisionWinnners[2], entity.GAME_TYPE_PLAYOFF_STAGE_1)
+
+	tournament, err = t.setStatus(*tournament, entity.TOURNAMENT_STATUS_CANCELLED)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+
+	return &AdvanceTournamentResponse{
+		StatusCode: http.StatusOK,
+		Tournament: tournament,
+	}, nil
 }
 
-func (t *TournamentUseCase) GenerateSemininalSchedule(tournamentID int) error {
-	winners, err := t.GameRepository.GetWinnersByType(tournamentID, entity.GAME_TYPE_PLAYOFF_STAGE_1)
+// RunTournament доигрывает турнир симулятором: шаги жизненного цикла проходятся до завершения турнира.
+// Каждый шаг сохраняется отдельной транзакцией, поэтому прерванный запуск продолжается с последней сохраненной стадии.
+func (t *TournamentUseCase) RunTournament(tournamentID int, req RunTournamentRequest) (*TournamentResultResponse, error) {
+	tournament, err := t.getTournament(tournamentID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if tournament.Status == entity.TOURNAMENT_STATUS_FINISHED {
+		return nil, InvalidState(ERROR_CODE_TOURNAMENT_FINISHED, "tournament is already finished")
+	}
+
+	for tournament.Status != entity.TOURNAMENT_STATUS_FINISHED {
+		tournament, _, err = t.advanceStep(tournament.ID, req.Seed, true)
+		if err != nil {
+			return nil, err
+		}
 	}
-	// Создаем пары для полуфинала
-	_, err = t.GameRepository.Create(tournamentID, winners[0], winners[3], entity.GAME_TYPE_PLAYOFF_SEMIFINAL)
+
+	return t.GetTournamentResult(tournament.ID)
+}
+
+// ResumeTournament продолжает прерванный турнир: этап турнира восстанавливается по сохраненным матчам,
+// и если последняя стадия решена, а следующая не создана, она создается. Результаты не симулируются.
+func (t *TournamentUseCase) ResumeTournament(tournamentID int) (*ResumeTournamentResponse, error) {
+	tournament, err := t.getTournament(tournamentID)
 	if err != nil {
+		return nil, err
+	}
+	if tournament.Status != entity.TOURNAMENT_STATUS_SEEDING && !inProgress(*tournament) {
+		return nil, InvalidState(ERROR_CODE_INVALID_STATUS_TRANSITION, "tournament in %s cannot be resumed", tournament.Status)
+	}
+
+	var games []entity.Game
+	err = t.inTransaction(tournament.ID, func(tx *TournamentUseCase, current entity.Tournament) error {
+		var finished bool
+		games, finished, err = tx.advanceStages(current)
+		if err != nil {
+			return err
+		}
+
+		tournament, err = tx.syncStatus(current, finished)
 		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := t.GameRepository.GetByTournament(tournament.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	_, err = t.GameRepository.Create(tournamentID, winners[1], winners[2], entity.GAME_TYPE_PLAYOFF_SEMIFINAL)
+	return &ResumeTournamentResponse{
+		StatusCode:         http.StatusOK,
+		Tournament:         tournament,
+		LastCompletedStage: lastCompletedStage(all),
+		Games:              games,
+	}, nil
+}
+
+// lastCompletedStage - номер последней стадии, до которой включительно все матчи решены.
+func lastCompletedStage(games []entity.Game) int {
+	completed := 0
+	for _, game := range games {
+		completed = max(completed, game.Stage)
+	}
+	for _, game := range games {
+		if !game.IsPlayed() {
+			completed = min(completed, game.Stage-1)
+		}
+	}
+	return max(completed, 0)
+}
+
+// simulateStage разыгрывает симулятором все матчи текущей стадии, у которых еще нет результата.
+func (t *TournamentUseCase) simulateStage(tournament entity.Tournament) error {
+	games, err := t.GameRepository.GetByTournament(tournament.ID)
 	if err != nil {
 		return err
 	}
 
+	gameTypes := []int{}
+	for _, game := range games {
+		if !game.IsPlayed() && !slices.Contains(gameTypes, game.GameType) {
+			gameTypes = append(gameTypes, game.GameType)
+		}
+	}
+	for _, gameType := range gameTypes {
+		err := t.GenerateResultByGameType(tournament, gameType)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (t *TournamentUseCase) GenerateFinalSchedule(tournamentID int) error {
-	winners, err := t.GameRepository.GetWinnersByType(tournamentID, entity.GAME_TYPE_PLAYOFF_SEMIFINAL)
+// ReportGameResult записывает результат сыгранного матча. Когда решены все матчи стадии,
+// сразу создаются матчи следующей, а после последней стадии сохраняются итоговые места.
+func (t *TournamentUseCase) ReportGameResult(tournamentID int, gameID int, req ReportGameResultRequest) (*ReportGameResultResponse, error) {
+	tournament, err := t.getTournament(tournamentID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if !inProgress(*tournament) {
+		return nil, InvalidState(ERROR_CODE_RESULTS_NOT_ACCEPTED, "results are not accepted while tournament is in %s", tournament.Status)
+	}
+
+	game, err := t.GameRepository.GetById(gameID)
+	if err != nil {
+		return nil, notFound(err, ERROR_CODE_GAME_NOT_FOUND, "game %d not found in tournament %d", gameID, tournament.ID)
 	}
-	_, err = t.GameRepository.Create(tournamentID, winners[0], winners[1], entity.GAME_TYPE_PLAYOFF_FINAL)
+	if game.TournamentID != tournament.ID {
+		return nil, NotFound(ERROR_CODE_GAME_NOT_FOUND, "game %d not found in tournament %d", gameID, tournament.ID)
+	}
+	if game.IsPlayed() {
+		return nil, Conflict(ERROR_CODE_RESULT_ALREADY_REPORTED, "game result has already been reported")
+	}
+
+	if (req.Team1Score == nil || req.Team2Score == nil) && len(req.Maps) == 0 {
+		return nil, Validation(ERROR_CODE_INVALID_SCORE, "team1_score and team2_score are required unless maps are given")
+	}
+	var team1Score, team2Score int
+	if req.Team1Score != nil && req.Team2Score != nil {
+		team1Score, team2Score = *req.Team1Score, *req.Team2Score
+	}
+
+	err = setGameResult(game, team1Score, team2Score, req.Maps)
 	if err != nil {
+		return nil, err
+	}
+	// winner_id необязателен и служит проверкой, что счет записан в правильном порядке команд
+	if req.WinnerID != nil && (game.WinnerId == nil || *game.WinnerId != *req.WinnerID) {
+		return nil, Validation(ERROR_CODE_INVALID_WINNER, "winner_id does not match the reported score")
+	}
+
+	// результат и созданная по нему следующая стадия сохраняются вместе
+	var games []entity.Game
+	err = t.inTransaction(tournament.ID, func(tx *TournamentUseCase, current entity.Tournament) error {
+		// турнир мог быть отменен, а результат записан параллельным запросом после проверок выше
+		if !inProgress(current) {
+			return InvalidState(ERROR_CODE_RESULTS_NOT_ACCEPTED, "results are not accepted while tournament is in %s", current.Status)
+		}
+		game, err = tx.GameRepository.Update(*game)
+		if errors.Is(err, sql.ErrNoRows) {
+			return Conflict(ERROR_CODE_RESULT_ALREADY_REPORTED, "game result has already been reported")
+		}
+		if err != nil {
+			return err
+		}
+
+		var finished bool
+		games, finished, err = tx.advanceStages(current)
+		if err != nil {
+			return err
+		}
+
+		tournament, err = tx.syncStatus(current, finished)
 		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReportGameResultResponse{
+		StatusCode: http.StatusOK,
+		Game:       game,
+		NextGames:  games,
+		Tournament: tournament,
+	}, nil
+}
+
+// fixSeed сохраняет сид турнира до первой стадии: все жеребьевки и результаты матчей выводятся из него.
+func (t *TournamentUseCase) fixSeed(tournament entity.Tournament, seed *int64) (*entity.Tournament, error) {
+	switch {
+	case tournament.Seed == nil && seed != nil:
+		tournament.Seed = seed
+	case tournament.Seed == nil:
+		seed := time.Now().UnixNano()
+		tournament.Seed = &seed
+	case seed != nil && *seed != *tournament.Seed:
+		return nil, Conflict(ERROR_CODE_SEED_ALREADY_SET, "tournament was already run with seed %d", *tournament.Seed)
+	default:
+		return &tournament, nil
+	}
+	return t.TournamentRepository.Update(tournament)
+}
+
+// advanceStages создает стадии, пока все матчи турнира решены: стадия из одних пропусков (bye)
+// решена сразу. Возвращает созданные матчи и true, если турнир завершен и места сохранены.
+func (t *TournamentUseCase) advanceStages(tournament entity.Tournament) ([]entity.Game, bool, error) {
+	format, err := GetFormat(tournament.Format)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var created []entity.Game
+	for {
+		state, err := t.formatState(tournament)
+		if err != nil {
+			return nil, false, err
+		}
+		stageNumber := 1
+		for _, game := range state.Games {
+			stageNumber = max(stageNumber, game.Stage+1)
+		}
+		for _, game := range state.Games {
+			if !game.IsPlayed() {
+				return created, false, nil
+			}
+		}
+
+		// формат решает, какие матчи играются на следующей стадии
+		stage, err := format.NextStage(*state)
+		if err != nil {
+			return nil, false, err
+		}
+		if stage == nil {
+			placements, err := format.Placements(*state)
+			if err != nil {
+				return nil, false, err
+			}
+			err = t.TournamentRepository.SavePlacements(tournament.ID, placements)
+			if err != nil {
+				return nil, false, err
+			}
+			return created, true, nil
+		}
+
+		// жребий сохраняется до матчей, чтобы его нельзя было подобрать под результат
+		err = t.TournamentRepository.AddLots(tournament.ID, stage.Lots)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, game := range stage.Games {
+			game.TournamentID = tournament.ID
+			game.Stage = stageNumber
+			game.BestOf = tournament.Settings.BestOfFor(game.GameType)
+			game.Status = entity.GAME_STATUS_SCHEDULED
+			if game.WinnerId != nil {
+				game.Status = entity.GAME_STATUS_FINISHED
+			}
+			res, err := t.GameRepository.Create(game)
+			if err != nil {
+				return nil, false, err
+			}
+			created = append(created, *res)
+		}
 	}
-	return nil
 }
 
-func (t *TournamentUseCase) GenerateFinalResult(tournamentID int) (*TournamentResultResponse, error) {
-	err := t.GenerateResultByGameType(tournamentID, entity.GAME_TYPE_PLAYOFF_FINAL)
+// GetTournamentResult возвращает сохраненные итоговые места уже проведенного турнира.
+func (t *TournamentUseCase) GetTournamentResult(tournamentID int) (*TournamentResultResponse, error) {
+	tournament, err := t.getTournament(tournamentID)
 	if err != nil {
 		return nil, err
 	}
-	winners, err := t.GameRepository.GetWinnersByType(tournamentID, entity.GAME_TYPE_PLAYOFF_FINAL)
 
+	placements, err := t.TournamentRepository.GetPlacements(tournament.ID)
 	if err != nil {
 		return nil, err
 	}
+	if len(placements) == 0 {
+		return nil, InvalidState(ERROR_CODE_TOURNAMENT_NOT_FINISHED, "tournament has not been played yet")
+	}
 
-	winner := winners[0]
+	state, err := t.formatState(*tournament)
+	if err != nil {
+		return nil, err
+	}
 
-	return &TournamentResultResponse{
+	return tournamentResult(*state, placements)
+}
+
+// tournamentResult собирает ответ по местам: победитель - первое место, призеры - места с первого по третье.
+func tournamentResult(state FormatState, placements []entity.Placement) (*TournamentResultResponse, error) {
+	res := &TournamentResultResponse{
 		StatusCode: http.StatusOK,
-		Winner:     winner,
+		Games:      state.Games,
+	}
+	if state.Tournament.Seed != nil {
+		res.Seed = *state.Tournament.Seed
+	}
+
+	slices.SortStableFunc(placements, func(a, b entity.Placement) int {
+		return a.PlaceFrom - b.PlaceFrom
+	})
+	for _, placement := range placements {
+		team, err := state.Team(placement.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		if placement.PlaceFrom == 1 {
+			res.Winner = team
+		}
+		if placement.PlaceFrom <= 3 {
+			res.Podium = append(res.Podium, team)
+		}
+		res.Placements = append(res.Placements, PlacementResponse{
+			PlaceFrom: placement.PlaceFrom,
+			PlaceTo:   placement.PlaceTo,
+			Team:      team,
+		})
+	}
+	return res, nil
+}
+
+// inTransaction выполняет fn с копией usecase, репозитории которой пишут в одну транзакцию.
+// Турнир заблокирован на время транзакции и перечитывается в ней: fn получает его текущее состояние,
+// а не прочитанное до блокировки, которое параллельный запрос мог уже изменить.
+func (t *TournamentUseCase) inTransaction(tournamentID int, fn func(tx *TournamentUseCase, tournament entity.Tournament) error) error {
+	return t.UnitOfWork.Do(tournamentID, func(tournamentRep TournamentRepository, gameRep GameRepository) error {
+		tx := &TournamentUseCase{
+			TournamentRepository: tournamentRep,
+			GameRepository:       gameRep,
+			UnitOfWork:           t.UnitOfWork,
+			MatchSimulator:       t.MatchSimulator,
+		}
+		tournament, err := tx.getTournament(tournamentID)
+		if err != nil {
+			return err
+		}
+		return fn(tx, *tournament)
+	})
+}
+
+// getTournament - турнир по id; если его нет, ошибка NotFound.
+func (t *TournamentUseCase) getTournament(tournamentID int) (*entity.Tournament, error) {
+	tournament, err := t.TournamentRepository.GetById(tournamentID)
+	if err != nil {
+		return nil, notFound(err, ERROR_CODE_TOURNAMENT_NOT_FOUND, "tournament %d not found", tournamentID)
+	}
+	return tournament, nil
+}
+
+func (t *TournamentUseCase) formatState(tournament entity.Tournament) (*FormatState, error) {
+	teams, err := t.TournamentRepository.GetTeams(tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	// форматы сеют команды по порядку: сначала посеянные по местам, затем остальные в порядке регистрации
+	slices.SortStableFunc(teams, compareSeeds)
+
+	games, err := t.GameRepository.GetByTournament(tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	lots, err := t.TournamentRepository.GetLots(tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FormatState{
+		Tournament: tournament,
+		Teams:      teams,
+		Games:      games,
+		Lots:       lots,
 	}, nil
 }
 
-func (t *TournamentUseCase) GenerateResultByGameType(tournamentID int, gameType int) error {
-	games, err := t.GameRepository.GetByTypeGames(tournamentID, gameType)
+// compareSeeds - порядок посева: команды без места (Seed == 0) идут после посеянных.
+func compareSeeds(a, b entity.Team) int {
+	switch {
+	case a.Seed == b.Seed:
+		return 0
+	case a.Seed == 0:
+		return 1
+	case b.Seed == 0:
+		return -1
+	}
+	return cmp.Compare(a.Seed, b.Seed)
+}
+
+func (t *TournamentUseCase) GenerateResultByGameType(tournament entity.Tournament, gameType int) error {
+	games, err := t.GameRepository.GetByTypeGames(tournament.ID, gameType)
+
+	if err != nil {
+		return err
+	}
 
+	teams, err := t.TournamentRepository.GetTeams(tournament.ID)
 	if err != nil {
 		return err
 	}
+	state := FormatState{Tournament: tournament, Teams: teams}
 
 	for i := 0; i < len(games); i++ {
-		winner := runGame(games[i].Team1ID, games[i].Team2ID)
-		games[i].WinnerId = &winner
-		//обновляем в базе инфу о победителе матча
-		_, err := t.GameRepository.Update(games[i])
+		if games[i].IsPlayed() {
+			continue
+		}
+		team1, err := state.Team(games[i].Team1ID)
+		if err != nil {
+			return err
+		}
+		team2, err := state.Team(games[i].Team2ID)
+		if err != nil {
+			return err
+		}
+		// у каждого матча свой генератор: результат не зависит от порядка розыгрыша
+		rng := state.Rand(RAND_GAME, games[i].GameType, games[i].Group, games[i].Round, games[i].Position)
+		team1Score, team2Score, maps := t.runGame(rng, games[i], team1, team2)
+		err = setGameResult(&games[i], team1Score, team2Score, maps)
+		if err != nil {
+			return err
+		}
+		//обновляем в базе счет и победителя матча
+		_, err = t.GameRepository.Update(games[i])
 		if err != nil {
 			return err
 		}
@@ -263,26 +931,93 @@ func (t *TournamentUseCase) GenerateResultByGameType(tournamentID int, gameType
 	return nil
 }
 
-func splitToDivisions(teams []entity.Team) ([]entity.Team, []entity.Team, error) {
-	if len(teams) != 16 {
-		return nil, nil, errors.New("expected 16 teams")
+// setGameResult записывает счет матча и определяет по нему победителя.
+// Если переданы карты, счет матча - количество выигранных карт.
+// Серия (BestOf > 1) завершается, только когда одна из команд набрала нужное число побед.
+func setGameResult(game *entity.Game, team1Score int, team2Score int, maps []entity.GameMap) error {
+	if game.IsBye() {
+		return InvalidState(ERROR_CODE_BYE_GAME, "bye game has no result to report")
+	}
+	if team1Score < 0 || team2Score < 0 {
+		return Validation(ERROR_CODE_INVALID_SCORE, "scores must not be negative")
 	}
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 
-	rng.Shuffle(len(teams), func(i, j int) {
-		teams[i], teams[j] = teams[j], teams[i]
-	})
+	if len(maps) > 0 {
+		for _, m := range maps {
+			if m.Team1Score < 0 || m.Team2Score < 0 {
+				return Validation(ERROR_CODE_INVALID_SCORE, "map scores must not be negative")
+			}
+		}
+		team1Maps, team2Maps := mapWins(maps)
+		if (team1Score != 0 || team2Score != 0) && (team1Score != team1Maps || team2Score != team2Maps) {
+			return Validation(ERROR_CODE_INVALID_SCORE, "score %d:%d does not match maps won %d:%d", team1Score, team2Score, team1Maps, team2Maps)
+		}
+		team1Score, team2Score = team1Maps, team2Maps
+	}
 
-	firstDivision := teams[:8]
-	secondDivision := teams[8:]
+	status := entity.GAME_STATUS_FINISHED
+	var winner *int
+	switch {
+	case game.BestOf > 1 && (team1Score > game.WinsNeeded() || team2Score > game.WinsNeeded() || team1Score == team2Score && team1Score == game.WinsNeeded()):
+		return Validation(ERROR_CODE_INVALID_SCORE, "invalid score %d:%d for best of %d", team1Score, team2Score, game.BestOf)
+	case game.BestOf > 1 && team1Score < game.WinsNeeded() && team2Score < game.WinsNeeded():
+		// серия еще не закончена, победитель не определен
+		status = entity.GAME_STATUS_IN_PROGRESS
+	case team1Score > team2Score:
+		id := game.Team1ID
+		winner = &id
+	case team2Score > team1Score:
+		id := game.Team2ID
+		winner = &id
+	case !game.AllowsDraw():
+		return Validation(ERROR_CODE_DRAW_NOT_ALLOWED, "this game cannot end in a draw")
+	}
+
+	game.Team1Score = &team1Score
+	game.Team2Score = &team2Score
+	game.Maps = maps
+	game.WinnerId = winner
+	game.Status = status
+	return nil
+}
 
-	return firstDivision, secondDivision, nil
+func mapWins(maps []entity.GameMap) (int, int) {
+	team1Wins, team2Wins := 0, 0
+	for _, m := range maps {
+		if m.Team1Score > m.Team2Score {
+			team1Wins++
+		} else if m.Team2Score > m.Team1Score {
+			team2Wins++
+		}
+	}
+	return team1Wins, team2Wins
 }
 
-func runGame(a int, b int) int {
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	if rng.Intn(2) == 0 {
-		return a
+// runGame разыгрывает матч симулятором. Серия доигрывается по картам
+// от уже сыгранных, пока одна из команд не наберет нужное число побед.
+func (t *TournamentUseCase) runGame(rng *rand.Rand, game entity.Game, team1 entity.Team, team2 entity.Team) (int, int, []entity.GameMap) {
+	if game.BestOf <= 1 {
+		team1Score, team2Score := t.MatchSimulator.Simulate(rng, team1, team2)
+		return team1Score, team2Score, nil
+	}
+
+	maps := slices.Clone(game.Maps)
+	team1Wins, team2Wins := mapWins(maps)
+	// серия, о которой сообщили только счет без карт, продолжается с этого счета и остается без карт
+	byScore := len(maps) == 0 && game.Team1Score != nil && game.Team2Score != nil
+	if byScore {
+		team1Wins, team2Wins = *game.Team1Score, *game.Team2Score
+	}
+	for team1Wins < game.WinsNeeded() && team2Wins < game.WinsNeeded() {
+		team1Score, team2Score := t.MatchSimulator.Simulate(rng, team1, team2)
+		if !byScore {
+			maps = append(maps, entity.GameMap{Team1Score: team1Score, Team2Score: team2Score})
+		}
+		if team1Score > team2Score {
+			team1Wins++
+		} else {
+			team2Wins++
+		}
 	}
-	return b
+	return team1Wins, team2Wins, maps
 }
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"testing"
+	"tournament/internal/entity"
+)
+
+func TestSetGameResult(t *testing.T) {
+	cases := []struct {
+		name       string
+		gameType   int
+		team2ID    int
+		team1Score int
+		team2Score int
+		maps       []entity.GameMap
+		winner     int
+		score      [2]int
+		invalid    bool
+	}{
+		{name: "first team wins", gameType: entity.GAME_TYPE_PLAYOFF_FINAL, team2ID: 2, team1Score: 3, team2Score: 1, winner: 1, score: [2]int{3, 1}},
+		{name: "second team wins", gameType: entity.GAME_TYPE_PLAYOFF_FINAL, team2ID: 2, team1Score: 0, team2Score: 2, winner: 2, score: [2]int{0, 2}},
+		{name: "draw in a group", gameType: entity.GAME_TYPE_GROUP, team2ID: 2, team1Score: 1, team2Score: 1, score: [2]int{1, 1}},
+		{name: "draw in playoff", gameType: entity.GAME_TYPE_PLAYOFF_FINAL, team2ID: 2, team1Score: 1, team2Score: 1, invalid: true},
+		{name: "negative score", gameType: entity.GAME_TYPE_GROUP, team2ID: 2, team1Score: -1, invalid: true},
+		{name: "bye", gameType: entity.GAME_TYPE_PLAYOFF_STAGE_1, team1Score: 1, invalid: true},
+		{
+			name: "score from maps", gameType: entity.GAME_TYPE_PLAYOFF_FINAL, team2ID: 2,
+			maps:   []entity.GameMap{{Team1Score: 16, Team2Score: 12}, {Team1Score: 9, Team2Score: 16}, {Team1Score: 10, Team2Score: 16}},
+			winner: 2, score: [2]int{1, 2},
+		},
+		{
+			name: "score matches maps", gameType: entity.GAME_TYPE_PLAYOFF_FINAL, team2ID: 2, team1Score: 2, team2Score: 0,
+			maps:   []entity.GameMap{{Team1Score: 16, Team2Score: 12}, {Team1Score: 16, Team2Score: 3}},
+			winner: 1, score: [2]int{2, 0},
+		},
+		{
+			name: "score does not match maps", gameType: entity.GAME_TYPE_PLAYOFF_FINAL, team2ID: 2, team1Score: 2, team2Score: 1,
+			maps:    []entity.GameMap{{Team1Score: 16, Team2Score: 12}, {Team1Score: 16, Team2Score: 3}},
+			invalid: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			game := entity.Game{Team1ID: 1, Team2ID: tc.team2ID, GameType: tc.gameType}
+			err := setGameResult(&game, tc.team1Score, tc.team2Score, tc.maps)
+			if tc.invalid {
+				if err == nil {
+					t.Errorf("no error, game %+v", game)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("set result: %v", err)
+			}
+			if !game.IsPlayed() {
+				t.Errorf("game status %d, want finished", game.Status)
+			}
+			if *game.Team1Score != tc.score[0] || *game.Team2Score != tc.score[1] {
+				t.Errorf("score %d:%d, want %d:%d", *game.Team1Score, *game.Team2Score, tc.score[0], tc.score[1])
+			}
+			if tc.winner == 0 && game.WinnerId != nil || tc.winner != 0 && (game.WinnerId == nil || *game.WinnerId != tc.winner) {
+				t.Errorf("winner %v, want %d", game.WinnerId, tc.winner)
+			}
+		})
+	}
+}
+
+func TestSetGameResultBestOf(t *testing.T) {
+	cases := []struct {
+		name       string
+		bestOf     int
+		team1Score int
+		team2Score int
+		maps       []entity.GameMap
+		status     int
+		winner     int
+		invalid    bool
+	}{
+		{name: "series won", bestOf: 3, team1Score: 2, team2Score: 1, status: entity.GAME_STATUS_FINISHED, winner: 1},
+		{name: "sweep", bestOf: 5, team1Score: 0, team2Score: 3, status: entity.GAME_STATUS_FINISHED, winner: 2},
+		{name: "series in progress", bestOf: 3, team1Score: 1, team2Score: 0, status: entity.GAME_STATUS_IN_PROGRESS},
+		{name: "series in progress by maps", bestOf: 5, maps: []entity.GameMap{{Team1Score: 16, Team2Score: 8}, {Team1Score: 7, Team2Score: 16}}, status: entity.GAME_STATUS_IN_PROGRESS},
+		{name: "more wins than needed", bestOf: 3, team1Score: 3, team2Score: 0, invalid: true},
+		{name: "both teams won", bestOf: 3, team1Score: 2, team2Score: 2, invalid: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			game := entity.Game{Team1ID: 1, Team2ID: 2, GameType: entity.GAME_TYPE_PLAYOFF_FINAL, BestOf: tc.bestOf}
+			err := setGameResult(&game, tc.team1Score, tc.team2Score, tc.maps)
+			if tc.invalid {
+				if err == nil {
+					t.Errorf("no error, game %+v", game)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("set result: %v", err)
+			}
+			if game.Status != tc.status {
+				t.Errorf("status %d, want %d", game.Status, tc.status)
+			}
+			if tc.winner == 0 && game.WinnerId != nil || tc.winner != 0 && (game.WinnerId == nil || *game.WinnerId != tc.winner) {
+				t.Errorf("winner %v, want %d", game.WinnerId, tc.winner)
+			}
+		})
+	}
+}
+
+func TestRunGameFinishesSeries(t *testing.T) {
+	played := []entity.GameMap{{Team1Score: 16, Team2Score: 10}, {Team1Score: 16, Team2Score: 14}}
+	game := entity.Game{Team1ID: 1, Team2ID: 2, GameType: entity.GAME_TYPE_PLAYOFF_FINAL, BestOf: 5, Maps: played}
+	uc := &TournamentUseCase{MatchSimulator: NewUniformSimulator()}
+
+	for i := 0; i < 20; i++ {
+		team1Score, team2Score, maps := uc.runGame(seededRand(int64(i)), game, entity.Team{ID: 1}, entity.Team{ID: 2})
+		if len(maps) < len(played) || maps[0] != played[0] || maps[1] != played[1] {
+			t.Fatalf("maps %v do not continue the played maps %v", maps, played)
+		}
+		if max(team1Score, team2Score) != game.WinsNeeded() || min(team1Score, team2Score) >= game.WinsNeeded() || team1Score < 2 {
+			t.Fatalf("series ended %d:%d after 2:0", team1Score, team2Score)
+		}
+		if err := setGameResult(&game, team1Score, team2Score, maps); err != nil {
+			t.Fatalf("simulated series: %v", err)
+		}
+		game.Maps, game.Status, game.WinnerId = played, 0, nil
+	}
+}
+
+func TestRunGameContinuesReportedScore(t *testing.T) {
+	team1Score, team2Score := 2, 0
+	game := entity.Game{Team1ID: 1, Team2ID: 2, GameType: entity.GAME_TYPE_PLAYOFF_FINAL, BestOf: 5, Team1Score: &team1Score, Team2Score: &team2Score, Status: entity.GAME_STATUS_IN_PROGRESS}
+	uc := &TournamentUseCase{MatchSimulator: NewUniformSimulator()}
+
+	for i := 0; i < 20; i++ {
+		score1, score2, maps := uc.runGame(seededRand(int64(i)), game, entity.Team{ID: 1}, entity.Team{ID: 2})
+		if maps != nil {
+			t.Fatalf("maps %v for a series reported by score", maps)
+		}
+		if score1 < 2 || max(score1, score2) != game.WinsNeeded() || score1+score2 > game.BestOf {
+			t.Fatalf("series ended %d:%d after 2:0", score1, score2)
+		}
+	}
+}
+
+func TestValidateBestOf(t *testing.T) {
+	valid := entity.TournamentSettings{BestOf: map[string]int{"final": 5, "semifinal": 3}}
+	if err := validateSettings(SingleEliminationFormat{}, valid); err != nil {
+		t.Errorf("best of %v: %v", valid.BestOf, err)
+	}
+	for _, bestOf := range []map[string]int{{"final": 4}, {"final": 9}, {"quarterfinal": 3}} {
+		if err := validateSettings(SingleEliminationFormat{}, entity.TournamentSettings{BestOf: bestOf}); err == nil {
+			t.Errorf("best of %v: no error", bestOf)
+		}
+	}
+}
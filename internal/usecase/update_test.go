@@ -0,0 +1,88 @@
+package usecase_test
+
+import (
+	"testing"
+	"tournament/internal/entity"
+	"tournament/internal/usecase"
+)
+
+func TestUpdateTournament(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 4)
+
+	name, format := "Spring Cup", "double_elimination"
+	res, err := uc.UpdateTournament(id, usecase.UpdateTournamentRequest{Name: &name, Format: &format, Settings: &entity.TournamentSettings{GrandFinalReset: true}})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if res.Tournament.Name != name || res.Tournament.Format != format || !res.Tournament.Settings.GrandFinalReset {
+		t.Errorf("updated %+v", res.Tournament)
+	}
+
+	// настройки проверяются вместе с форматом
+	classic := "classic"
+	if _, err := uc.UpdateTournament(id, usecase.UpdateTournamentRequest{Format: &classic, Settings: &entity.TournamentSettings{GroupSize: 1}}); err == nil {
+		t.Error("classic with groups of one team: no error")
+	}
+	unknown := "round_robin_deluxe"
+	if _, err := uc.UpdateTournament(id, usecase.UpdateTournamentRequest{Format: &unknown}); err == nil {
+		t.Error("unknown format: no error")
+	}
+
+	if _, err := uc.StartTournament(id, usecase.RunTournamentRequest{}); err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+	if _, err := uc.UpdateTournament(id, usecase.UpdateTournamentRequest{Settings: &entity.TournamentSettings{}}); errorCode(err) != usecase.ERROR_CODE_TOURNAMENT_STARTED {
+		t.Errorf("settings changed after start: error %v, want %s", err, usecase.ERROR_CODE_TOURNAMENT_STARTED)
+	}
+	name = "Summer Cup"
+	if _, err := uc.UpdateTournament(id, usecase.UpdateTournamentRequest{Name: &name}); err != nil {
+		t.Errorf("rename after start: %v", err)
+	}
+}
+
+func TestUpdateAndDeleteTeam(t *testing.T) {
+	uc := newUseCase()
+	id, teams := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 3)
+	otherID, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 0)
+
+	rating := 0
+	res, err := uc.UpdateTeam(id, teams[0].ID, usecase.UpdateTeamRequest{Rating: &rating})
+	if err != nil {
+		t.Fatalf("update team: %v", err)
+	}
+	if res.Team.Rating != entity.DEFAULT_RATING {
+		t.Errorf("rating %d, want default %d", res.Team.Rating, entity.DEFAULT_RATING)
+	}
+
+	// команда другого турнира не найдена
+	if _, err := uc.UpdateTeam(otherID, teams[0].ID, usecase.UpdateTeamRequest{Rating: &rating}); errorCode(err) != usecase.ERROR_CODE_TEAM_NOT_FOUND {
+		t.Errorf("team updated through another tournament: error %v, want %s", err, usecase.ERROR_CODE_TEAM_NOT_FOUND)
+	}
+	if _, err := uc.DeleteTeam(otherID, teams[0].ID); errorCode(err) != usecase.ERROR_CODE_TEAM_NOT_FOUND {
+		t.Errorf("team deleted through another tournament: error %v, want %s", err, usecase.ERROR_CODE_TEAM_NOT_FOUND)
+	}
+
+	if _, err := uc.DeleteTeam(id, teams[2].ID); err != nil {
+		t.Fatalf("delete team: %v", err)
+	}
+	list, err := uc.ListTeams(id, usecase.ListRequest{})
+	if err != nil || len(list.Teams) != 2 {
+		t.Errorf("teams after delete %+v: %v", list, err)
+	}
+
+	if _, err := uc.StartTournament(id, usecase.RunTournamentRequest{}); err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+	name := "Renamed"
+	if _, err := uc.UpdateTeam(id, teams[0].ID, usecase.UpdateTeamRequest{Name: &name}); err != nil {
+		t.Errorf("rename after start: %v", err)
+	}
+	rating = 1700
+	if _, err := uc.UpdateTeam(id, teams[0].ID, usecase.UpdateTeamRequest{Rating: &rating}); err == nil {
+		t.Error("rating changed after registration")
+	}
+	if _, err := uc.DeleteTeam(id, teams[1].ID); err == nil {
+		t.Error("team deleted after registration")
+	}
+}
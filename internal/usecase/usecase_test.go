@@ -0,0 +1,727 @@
+package usecase_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"tournament/internal/entity"
+	"tournament/internal/repository/memory"
+	"tournament/internal/usecase"
+)
+
+// newUseCase - сценарии на хранилище в памяти, у каждого теста свое.
+func newUseCase() *usecase.TournamentUseCase {
+	store := memory.NewStore()
+	return usecase.NewTournamentUsecase(
+		memory.NewTournamentRepository(store),
+		memory.NewGameRepository(store),
+		memory.NewUnitOfWork(store),
+		usecase.NewEloSimulator(),
+	)
+}
+
+// createTournament создает турнир с teams командами, посеянными по порядку регистрации.
+func createTournament(t *testing.T, uc *usecase.TournamentUseCase, format string, settings entity.TournamentSettings, teams int) (int, []entity.Team) {
+	t.Helper()
+	created, err := uc.CreateTournament(usecase.CreateTournamentRequest{Name: "Test Cup", Format: format, Settings: settings})
+	if err != nil {
+		t.Fatalf("create tournament: %v", err)
+	}
+
+	var registered []entity.Team
+	for i := 1; i <= teams; i++ {
+		added, err := uc.AddTeam(created.Tournament.ID, usecase.AddTeamRequest{
+			Name:   fmt.Sprintf("Team %d", i),
+			Rating: 1000 + 50*(i%5),
+		})
+		if err != nil {
+			t.Fatalf("add team %d: %v", i, err)
+		}
+		registered = append(registered, *added.Team)
+	}
+	return created.Tournament.ID, registered
+}
+
+func reportResult(t *testing.T, uc *usecase.TournamentUseCase, tournamentID int, game entity.Game, team1Score int, team2Score int) *usecase.ReportGameResultResponse {
+	t.Helper()
+	res, err := uc.ReportGameResult(tournamentID, game.ID, usecase.ReportGameResultRequest{Team1Score: &team1Score, Team2Score: &team2Score})
+	if err != nil {
+		t.Fatalf("report result of game %d: %v", game.ID, err)
+	}
+	return res
+}
+
+func errorCode(err error) string {
+	var domainErr *usecase.Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+	return ""
+}
+
+var formatCases = []struct {
+	name     string
+	format   string
+	settings entity.TournamentSettings
+	teams    int
+}{
+	{name: "classic", format: "classic", teams: 10, settings: entity.TournamentSettings{Groups: 2, AdvancePerGroup: 2}},
+	{name: "classic with partial playoff", format: "classic", teams: 12, settings: entity.TournamentSettings{Groups: 3, AdvancePerGroup: 2}},
+	{name: "classic with crossover", format: "classic", teams: 16, settings: entity.TournamentSettings{Groups: 2, AdvancePerGroup: 2, Crossover: []string{"A1-B2", "B1-A2"}}},
+	{name: "single elimination", format: "single_elimination", teams: 6},
+	{name: "single elimination with third place", format: "single_elimination", teams: 11, settings: entity.TournamentSettings{ThirdPlaceMatch: true}},
+	{name: "double elimination", format: "double_elimination", teams: 7, settings: entity.TournamentSettings{GrandFinalReset: true}},
+	{name: "swiss", format: "swiss", teams: 9},
+}
+
+func TestRunTournamentIsReproducibleWithSeed(t *testing.T) {
+	seed := int64(20261018)
+	for _, tc := range formatCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var results []*usecase.TournamentResultResponse
+			for run := 0; run < 2; run++ {
+				uc := newUseCase()
+				id, _ := createTournament(t, uc, tc.format, tc.settings, tc.teams)
+				result, err := uc.RunTournament(id, usecase.RunTournamentRequest{Seed: &seed})
+				if err != nil {
+					t.Fatalf("run tournament: %v", err)
+				}
+				results = append(results, result)
+			}
+
+			if results[0].Seed != seed {
+				t.Errorf("seed = %d, want %d", results[0].Seed, seed)
+			}
+			if !reflect.DeepEqual(results[0], results[1]) {
+				t.Errorf("runs with the same seed differ:\n%+v\n%+v", results[0], results[1])
+			}
+		})
+	}
+}
+
+func TestConcurrentRunTournament(t *testing.T) {
+	seed := int64(20261018)
+	for _, tc := range formatCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := newUseCase()
+			id, _ := createTournament(t, uc, tc.format, tc.settings, tc.teams)
+			want, err := uc.RunTournament(id, usecase.RunTournamentRequest{Seed: &seed})
+			if err != nil {
+				t.Fatalf("run tournament: %v", err)
+			}
+
+			// параллельные запуски проходят шаги по очереди, и ни одна стадия не создается дважды
+			uc = newUseCase()
+			id, _ = createTournament(t, uc, tc.format, tc.settings, tc.teams)
+			var wg sync.WaitGroup
+			errs := make(chan error, 8)
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := uc.RunTournament(id, usecase.RunTournamentRequest{Seed: &seed})
+					if err != nil && errorCode(err) != usecase.ERROR_CODE_TOURNAMENT_FINISHED {
+						errs <- err
+					}
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				t.Errorf("parallel run: %v", err)
+			}
+
+			got, err := uc.GetTournamentResult(id)
+			if err != nil {
+				t.Fatalf("result: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("parallel runs differ from a single one:\n%+v\n%+v", got, want)
+			}
+		})
+	}
+}
+
+func TestRunTournamentKeepsSeed(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 4)
+
+	seed := int64(7)
+	if _, err := uc.StartTournament(id, usecase.RunTournamentRequest{Seed: &seed}); err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+
+	other := int64(8)
+	_, err := uc.RunTournament(id, usecase.RunTournamentRequest{Seed: &other})
+	if code := errorCode(err); code != usecase.ERROR_CODE_SEED_ALREADY_SET {
+		t.Fatalf("run with another seed: error %v, want %s", err, usecase.ERROR_CODE_SEED_ALREADY_SET)
+	}
+
+	result, err := uc.RunTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("run tournament: %v", err)
+	}
+	if result.Seed != seed {
+		t.Errorf("seed = %d, want %d", result.Seed, seed)
+	}
+}
+
+func TestEveryTeamReceivesPlacement(t *testing.T) {
+	for _, tc := range formatCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := newUseCase()
+			id, teams := createTournament(t, uc, tc.format, tc.settings, tc.teams)
+			result, err := uc.RunTournament(id, usecase.RunTournamentRequest{})
+			if err != nil {
+				t.Fatalf("run tournament: %v", err)
+			}
+
+			placements := make([]entity.Placement, 0, len(result.Placements))
+			for _, placement := range result.Placements {
+				placements = append(placements, entity.Placement{TeamID: placement.Team.ID, PlaceFrom: placement.PlaceFrom, PlaceTo: placement.PlaceTo})
+			}
+			checkPlacements(t, placements, teams)
+		})
+	}
+}
+
+// checkPlacements проверяет, что каждая команда получила одно место, а места идут без пропусков:
+// на месте "с-по" столько команд, сколько в нем мест.
+func checkPlacements(t *testing.T, placements []entity.Placement, teams []entity.Team) {
+	t.Helper()
+	placed := map[int]int{}
+	shared := map[[2]int]int{}
+	for _, placement := range placements {
+		placed[placement.TeamID]++
+		shared[[2]int{placement.PlaceFrom, placement.PlaceTo}]++
+	}
+	for _, team := range teams {
+		if placed[team.ID] != 1 {
+			t.Errorf("team %d placed %d times, want once", team.ID, placed[team.ID])
+		}
+	}
+
+	next := 1
+	for next <= len(teams) {
+		width := 0
+		for places, count := range shared {
+			if places[0] != next {
+				continue
+			}
+			width = places[1] - places[0] + 1
+			if count != width {
+				t.Errorf("places %d-%d are shared by %d teams, want %d", places[0], places[1], count, width)
+			}
+		}
+		if width == 0 {
+			t.Fatalf("no team on place %d", next)
+		}
+		next += width
+	}
+}
+
+func TestReportGameResultValidatesBestOf(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{BestOf: map[string]int{"semifinal": 3}}, 4)
+	started, err := uc.StartTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+	game := started.Games[0]
+	if game.BestOf != 3 {
+		t.Fatalf("semifinal best of %d, want 3", game.BestOf)
+	}
+
+	invalid := []struct {
+		name       string
+		team1Score int
+		team2Score int
+		maps       []entity.GameMap
+	}{
+		{name: "more wins than needed", team1Score: 3, team2Score: 0},
+		{name: "both teams won the series", team1Score: 2, team2Score: 2},
+		{name: "score does not match maps", team1Score: 1, team2Score: 2, maps: []entity.GameMap{{Team1Score: 16, Team2Score: 10}, {Team1Score: 16, Team2Score: 12}}},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := uc.ReportGameResult(id, game.ID, usecase.ReportGameResultRequest{Team1Score: &tc.team1Score, Team2Score: &tc.team2Score, Maps: tc.maps})
+			if code := errorCode(err); code != usecase.ERROR_CODE_INVALID_SCORE {
+				t.Errorf("error %v, want %s", err, usecase.ERROR_CODE_INVALID_SCORE)
+			}
+		})
+	}
+
+	// неоконченная серия сохраняется без победителя и дописывается следующим результатом
+	res := reportResult(t, uc, id, game, 1, 0)
+	if res.Game.Status != entity.GAME_STATUS_IN_PROGRESS || res.Game.WinnerId != nil {
+		t.Errorf("series at 1:0 has status %d and winner %v, want in progress without winner", res.Game.Status, res.Game.WinnerId)
+	}
+
+	maps := []entity.GameMap{{Team1Score: 16, Team2Score: 10}, {Team1Score: 12, Team2Score: 16}, {Team1Score: 16, Team2Score: 14}}
+	res, err = uc.ReportGameResult(id, game.ID, usecase.ReportGameResultRequest{Maps: maps})
+	if err != nil {
+		t.Fatalf("report series by maps: %v", err)
+	}
+	if res.Game.Status != entity.GAME_STATUS_FINISHED || res.Game.WinnerId == nil || *res.Game.WinnerId != game.Team1ID {
+		t.Errorf("series 2:1 on maps has status %d and winner %v, want finished with team %d", res.Game.Status, res.Game.WinnerId, game.Team1ID)
+	}
+	if *res.Game.Team1Score != 2 || *res.Game.Team2Score != 1 {
+		t.Errorf("series score %d:%d, want 2:1", *res.Game.Team1Score, *res.Game.Team2Score)
+	}
+
+	_, err = uc.ReportGameResult(id, game.ID, usecase.ReportGameResultRequest{Maps: maps})
+	if code := errorCode(err); code != usecase.ERROR_CODE_RESULT_ALREADY_REPORTED {
+		t.Errorf("second report: error %v, want %s", err, usecase.ERROR_CODE_RESULT_ALREADY_REPORTED)
+	}
+}
+
+func TestRunTournamentKeepsReportedSeriesScore(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		uc := newUseCase()
+		id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{BestOf: map[string]int{"final": 5}}, 2)
+		started, err := uc.StartTournament(id, usecase.RunTournamentRequest{Seed: &seed})
+		if err != nil {
+			t.Fatalf("start tournament: %v", err)
+		}
+		final := started.Games[0]
+		if res := reportResult(t, uc, id, final, 0, 2); res.Game.Status != entity.GAME_STATUS_IN_PROGRESS {
+			t.Fatalf("series 0:2 of best of 5 has status %d", res.Game.Status)
+		}
+
+		if _, err := uc.RunTournament(id, usecase.RunTournamentRequest{}); err != nil {
+			t.Fatalf("run tournament: %v", err)
+		}
+		games, err := uc.ListGames(id, usecase.ListGamesRequest{})
+		if err != nil {
+			t.Fatalf("games: %v", err)
+		}
+		game := games.Games[0]
+		if game.Status != entity.GAME_STATUS_FINISHED || *game.Team2Score < 2 || max(*game.Team1Score, *game.Team2Score) != 3 || *game.Team1Score+*game.Team2Score > 5 {
+			t.Errorf("seed %d: series reported at 0:2 ended %d:%d", seed, *game.Team1Score, *game.Team2Score)
+		}
+	}
+}
+
+// racingGames - матчи, результат которых записывает параллельный запрос сразу после чтения.
+type racingGames struct {
+	usecase.GameRepository
+}
+
+func (g racingGames) GetById(id int) (*entity.Game, error) {
+	game, err := g.GameRepository.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	parallel := *game
+	team1Score, team2Score := 1, 0
+	parallel.Team1Score, parallel.Team2Score = &team1Score, &team2Score
+	parallel.WinnerId = &parallel.Team1ID
+	parallel.Status = entity.GAME_STATUS_FINISHED
+	if _, err := g.GameRepository.Update(parallel); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// cancellingGames - матчи турнира, который параллельный запрос отменяет сразу после чтения матча.
+type cancellingGames struct {
+	usecase.GameRepository
+	tournaments usecase.TournamentRepository
+}
+
+func (g cancellingGames) GetById(id int) (*entity.Game, error) {
+	game, err := g.GameRepository.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	tournament, err := g.tournaments.GetById(game.TournamentID)
+	if err != nil {
+		return nil, err
+	}
+	tournament.Status = entity.TOURNAMENT_STATUS_CANCELLED
+	if _, err := g.tournaments.Update(*tournament); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+func TestReportGameResultRereadsTournament(t *testing.T) {
+	store := memory.NewStore()
+	tournaments := memory.NewTournamentRepository(store)
+	games := memory.NewGameRepository(store)
+	uc := usecase.NewTournamentUsecase(tournaments, cancellingGames{games, tournaments}, memory.NewUnitOfWork(store), usecase.NewEloSimulator())
+	id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 2)
+	started, err := uc.StartTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+
+	team1Score, team2Score := 1, 0
+	game := started.Games[0]
+	_, err = uc.ReportGameResult(id, game.ID, usecase.ReportGameResultRequest{Team1Score: &team1Score, Team2Score: &team2Score})
+	if code := errorCode(err); code != usecase.ERROR_CODE_RESULTS_NOT_ACCEPTED {
+		t.Errorf("report to a tournament cancelled meanwhile: error %v, want %s", err, usecase.ERROR_CODE_RESULTS_NOT_ACCEPTED)
+	}
+	if saved, err := games.GetById(game.ID); err != nil || saved.IsPlayed() {
+		t.Errorf("game of the cancelled tournament %+v, %v, want it unplayed", saved, err)
+	}
+}
+
+func TestReportGameResultRejectsParallelReport(t *testing.T) {
+	store := memory.NewStore()
+	games := memory.NewGameRepository(store)
+	uc := usecase.NewTournamentUsecase(memory.NewTournamentRepository(store), racingGames{games}, memory.NewUnitOfWork(store), usecase.NewEloSimulator())
+	id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 2)
+	started, err := uc.StartTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+
+	team1Score, team2Score := 0, 1
+	game := started.Games[0]
+	_, err = uc.ReportGameResult(id, game.ID, usecase.ReportGameResultRequest{Team1Score: &team1Score, Team2Score: &team2Score})
+	if code := errorCode(err); code != usecase.ERROR_CODE_RESULT_ALREADY_REPORTED {
+		t.Errorf("report after a parallel one: error %v, want %s", err, usecase.ERROR_CODE_RESULT_ALREADY_REPORTED)
+	}
+	// сохранен результат параллельного запроса
+	saved, err := games.GetById(game.ID)
+	if err != nil || *saved.WinnerId != game.Team1ID {
+		t.Errorf("saved game %+v, %v, want team %d to win", saved, err, game.Team1ID)
+	}
+}
+
+func TestReportGameResultAdvancesStages(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 4)
+	started, err := uc.StartTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+	if started.Tournament.Status != entity.TOURNAMENT_STATUS_PLAYOFFS || len(started.Games) != 2 {
+		t.Fatalf("started in %s with %d games, want playoffs with 2", started.Tournament.Status, len(started.Games))
+	}
+
+	// следующая стадия создается только после последнего результата текущей
+	if res := reportResult(t, uc, id, started.Games[0], 1, 0); len(res.NextGames) != 0 {
+		t.Errorf("%d games created before the stage is decided", len(res.NextGames))
+	}
+	res := reportResult(t, uc, id, started.Games[1], 0, 1)
+	final := findGame(t, res.NextGames, entity.GAME_TYPE_PLAYOFF_FINAL, 2)
+	checkTeams(t, final, started.Games[0].Team1ID, started.Games[1].Team2ID)
+
+	res = reportResult(t, uc, id, final, 1, 0)
+	if res.Tournament.Status != entity.TOURNAMENT_STATUS_FINISHED {
+		t.Errorf("status %s after the final, want finished", res.Tournament.Status)
+	}
+	result, err := uc.GetTournamentResult(id)
+	if err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	if result.Winner.ID != final.Team1ID {
+		t.Errorf("winner %d, want %d", result.Winner.ID, final.Team1ID)
+	}
+}
+
+func TestTournamentLifecycle(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "classic", entity.TournamentSettings{Groups: 2, AdvancePerGroup: 2}, 8)
+
+	// результаты принимаются только во время турнира
+	steps := []string{
+		entity.TOURNAMENT_STATUS_SEEDING,
+		entity.TOURNAMENT_STATUS_GROUP_STAGE,
+	}
+	for _, status := range steps {
+		res, err := uc.AdvanceTournament(id, usecase.RunTournamentRequest{})
+		if err != nil {
+			t.Fatalf("advance to %s: %v", status, err)
+		}
+		if res.Tournament.Status != status {
+			t.Fatalf("advanced to %s, want %s", res.Tournament.Status, status)
+		}
+	}
+	_, err := uc.AddTeam(id, usecase.AddTeamRequest{Name: "Late"})
+	if code := errorCode(err); code != usecase.ERROR_CODE_REGISTRATION_CLOSED {
+		t.Errorf("team added after registration closed: error %v, want %s", err, usecase.ERROR_CODE_REGISTRATION_CLOSED)
+	}
+
+	if _, err := uc.CancelTournament(id); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	_, err = uc.AdvanceTournament(id, usecase.RunTournamentRequest{})
+	if code := errorCode(err); code != usecase.ERROR_CODE_TOURNAMENT_CANCELLED {
+		t.Errorf("cancelled tournament advanced: error %v, want %s", err, usecase.ERROR_CODE_TOURNAMENT_CANCELLED)
+	}
+	if _, err := uc.RunTournament(id, usecase.RunTournamentRequest{}); err == nil {
+		t.Error("cancelled tournament run")
+	}
+}
+
+func TestAdvanceDoesNotSimulateGames(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 4)
+	started, err := uc.StartTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+
+	_, err = uc.AdvanceTournament(id, usecase.RunTournamentRequest{})
+	if code := errorCode(err); code != usecase.ERROR_CODE_GAMES_PENDING {
+		t.Errorf("advance with unfinished games: error %v, want %s", err, usecase.ERROR_CODE_GAMES_PENDING)
+	}
+	games, err := uc.ListGames(id, usecase.ListGamesRequest{})
+	if err != nil {
+		t.Fatalf("games: %v", err)
+	}
+	for _, game := range games.Games {
+		if game.IsPlayed() {
+			t.Errorf("game %d was played by advance", game.ID)
+		}
+	}
+
+	// после внесенных результатов полуфиналов финал уже создан, а доиграть его может только запуск
+	for _, game := range started.Games {
+		reportResult(t, uc, id, game, 1, 0)
+	}
+	_, err = uc.AdvanceTournament(id, usecase.RunTournamentRequest{})
+	if code := errorCode(err); code != usecase.ERROR_CODE_GAMES_PENDING {
+		t.Errorf("advance with an unfinished final: error %v, want %s", err, usecase.ERROR_CODE_GAMES_PENDING)
+	}
+	if _, err := uc.RunTournament(id, usecase.RunTournamentRequest{}); err != nil {
+		t.Errorf("run tournament: %v", err)
+	}
+}
+
+func TestAdvanceRequiresTwoTeams(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 1)
+	_, err := uc.AdvanceTournament(id, usecase.RunTournamentRequest{})
+	if code := errorCode(err); code != usecase.ERROR_CODE_NOT_ENOUGH_TEAMS {
+		t.Errorf("registration closed with one team: error %v, want %s", err, usecase.ERROR_CODE_NOT_ENOUGH_TEAMS)
+	}
+}
+
+func TestAdvanceChecksFirstStage(t *testing.T) {
+	uc := newUseCase()
+	// в четыре группы нужно хотя бы восемь команд
+	id, _ := createTournament(t, uc, "classic", entity.TournamentSettings{Groups: 4}, 5)
+	_, err := uc.AdvanceTournament(id, usecase.RunTournamentRequest{})
+	if code := errorCode(err); code != usecase.ERROR_CODE_NOT_ENOUGH_TEAMS {
+		t.Errorf("registration closed with 5 teams in 4 groups: error %v, want %s", err, usecase.ERROR_CODE_NOT_ENOUGH_TEAMS)
+	}
+	tournament, err := uc.GetTournament(id)
+	if err != nil || tournament.Tournament.Status != entity.TOURNAMENT_STATUS_REGISTRATION {
+		t.Fatalf("tournament after failed advance: %+v, %v", tournament, err)
+	}
+
+	// при посеве настройки, с которыми турнир не начать, не принимаются
+	if _, err := uc.UpdateTournament(id, usecase.UpdateTournamentRequest{Settings: &entity.TournamentSettings{Groups: 2}}); err != nil {
+		t.Fatalf("update settings: %v", err)
+	}
+	if _, err := uc.AdvanceTournament(id, usecase.RunTournamentRequest{}); err != nil {
+		t.Fatalf("close registration: %v", err)
+	}
+	_, err = uc.UpdateTournament(id, usecase.UpdateTournamentRequest{Settings: &entity.TournamentSettings{Groups: 3}})
+	if code := errorCode(err); code != usecase.ERROR_CODE_NOT_ENOUGH_TEAMS {
+		t.Errorf("3 groups for 5 teams in seeding: error %v, want %s", err, usecase.ERROR_CODE_NOT_ENOUGH_TEAMS)
+	}
+}
+
+func TestResumeTournamentCreatesMissingStage(t *testing.T) {
+	store := memory.NewStore()
+	games := memory.NewGameRepository(store)
+	uc := usecase.NewTournamentUsecase(memory.NewTournamentRepository(store), games, memory.NewUnitOfWork(store), usecase.NewEloSimulator())
+	id, _ := createTournament(t, uc, "single_elimination", entity.TournamentSettings{}, 4)
+	started, err := uc.StartTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+
+	res, err := uc.ResumeTournament(id)
+	if err != nil {
+		t.Fatalf("resume with pending games: %v", err)
+	}
+	if len(res.Games) != 0 || res.LastCompletedStage != 0 {
+		t.Errorf("resume with pending games created %d games, last completed stage %d", len(res.Games), res.LastCompletedStage)
+	}
+
+	// результаты сохранены в обход ReportGameResult, как если бы процесс упал до создания следующей стадии
+	for _, game := range started.Games {
+		team1Score, team2Score := 1, 0
+		game.Team1Score, game.Team2Score = &team1Score, &team2Score
+		game.WinnerId = &game.Team1ID
+		game.Status = entity.GAME_STATUS_FINISHED
+		if _, err := games.Update(game); err != nil {
+			t.Fatalf("save game %d: %v", game.ID, err)
+		}
+	}
+
+	res, err = uc.ResumeTournament(id)
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if res.LastCompletedStage != 1 {
+		t.Errorf("last completed stage %d, want 1", res.LastCompletedStage)
+	}
+	findGame(t, res.Games, entity.GAME_TYPE_PLAYOFF_FINAL, 2)
+
+	if res, err := uc.ResumeTournament(id); err != nil || len(res.Games) != 0 {
+		t.Errorf("second resume created %d games: %v", len(res.Games), err)
+	}
+}
+
+// TestResumeFinishesTournamentWithoutPlacements повторяет миграцию статусов: турнир, сыгранный
+// до появления итоговых мест, остается на последней стадии, и resume сохраняет места и завершает его.
+func TestResumeFinishesTournamentWithoutPlacements(t *testing.T) {
+	cases := []struct {
+		format   string
+		settings entity.TournamentSettings
+		status   string
+	}{
+		{format: "classic", settings: entity.TournamentSettings{Groups: 2, AdvancePerGroup: 2}, status: entity.TOURNAMENT_STATUS_PLAYOFFS},
+		{format: "swiss", status: entity.TOURNAMENT_STATUS_GROUP_STAGE},
+	}
+	for _, tc := range cases {
+		t.Run(tc.format, func(t *testing.T) {
+			uc := newUseCase()
+			id, teams := createTournament(t, uc, tc.format, tc.settings, 8)
+			if _, err := uc.RunTournament(id, usecase.RunTournamentRequest{}); err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			tournament, err := uc.TournamentRepository.GetById(id)
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			tournament.Status = tc.status
+			if _, err := uc.TournamentRepository.Update(*tournament); err != nil {
+				t.Fatalf("update: %v", err)
+			}
+			if err := uc.TournamentRepository.SavePlacements(id, nil); err != nil {
+				t.Fatalf("clear placements: %v", err)
+			}
+
+			res, err := uc.ResumeTournament(id)
+			if err != nil {
+				t.Fatalf("resume: %v", err)
+			}
+			if len(res.Games) != 0 || res.Tournament.Status != entity.TOURNAMENT_STATUS_FINISHED {
+				t.Errorf("resume created %d games, status %s", len(res.Games), res.Tournament.Status)
+			}
+			placements, err := uc.TournamentRepository.GetPlacements(id)
+			if err != nil {
+				t.Fatalf("placements: %v", err)
+			}
+			checkPlacements(t, placements, teams)
+		})
+	}
+}
+
+func TestByesGoToTopSeeds(t *testing.T) {
+	cases := []struct {
+		format string
+		teams  int
+		byes   int
+	}{
+		{format: "single_elimination", teams: 6, byes: 2},
+		{format: "single_elimination", teams: 8, byes: 0},
+		{format: "single_elimination", teams: 13, byes: 3},
+		{format: "double_elimination", teams: 5, byes: 3},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%s with %d teams", tc.format, tc.teams), func(t *testing.T) {
+			uc := newUseCase()
+			id, teams := createTournament(t, uc, tc.format, entity.TournamentSettings{}, tc.teams)
+			started, err := uc.StartTournament(id, usecase.RunTournamentRequest{})
+			if err != nil {
+				t.Fatalf("start tournament: %v", err)
+			}
+
+			byes := map[int]bool{}
+			playing := map[int]bool{}
+			for _, game := range started.Games {
+				if game.Round != 1 {
+					t.Fatalf("game %d of round %d created before round 1 is played", game.ID, game.Round)
+				}
+				if game.IsBye() {
+					if game.WinnerId == nil || *game.WinnerId != game.Team1ID {
+						t.Errorf("bye of team %d has winner %v", game.Team1ID, game.WinnerId)
+					}
+					byes[game.Team1ID] = true
+					continue
+				}
+				playing[game.Team1ID] = true
+				playing[game.Team2ID] = true
+			}
+
+			if len(byes) != tc.byes {
+				t.Errorf("%d byes, want %d", len(byes), tc.byes)
+			}
+			for i, team := range teams {
+				if i < tc.byes && !byes[team.ID] {
+					t.Errorf("seed %d has no bye", i+1)
+				}
+				if i >= tc.byes && !playing[team.ID] {
+					t.Errorf("seed %d does not play in round 1", i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestDoubleEliminationReportedResults(t *testing.T) {
+	uc := newUseCase()
+	id, _ := createTournament(t, uc, "double_elimination", entity.TournamentSettings{}, 4)
+	started, err := uc.StartTournament(id, usecase.RunTournamentRequest{})
+	if err != nil {
+		t.Fatalf("start tournament: %v", err)
+	}
+	if len(started.Games) != 2 {
+		t.Fatalf("%d games in the first stage, want 2", len(started.Games))
+	}
+
+	// в каждом матче верхней сетки побеждает первая команда
+	var next []entity.Game
+	for _, game := range started.Games {
+		next = append(next, reportResult(t, uc, id, game, 1, 0).NextGames...)
+	}
+	upperFinal := findGame(t, next, entity.GAME_TYPE_WINNERS_BRACKET, 2)
+	lower := findGame(t, next, entity.GAME_TYPE_LOSERS_BRACKET, 1)
+
+	first, second := started.Games[0], started.Games[1]
+	checkTeams(t, upperFinal, first.Team1ID, second.Team1ID)
+	checkTeams(t, lower, first.Team2ID, second.Team2ID)
+	if lower.Team1SourceGameID != first.ID || lower.Team2SourceGameID != second.ID {
+		t.Errorf("lower bracket game comes from games %d and %d, want %d and %d", lower.Team1SourceGameID, lower.Team2SourceGameID, first.ID, second.ID)
+	}
+
+	// проигравший финала верхней сетки получает второй шанс в финале нижней
+	next = reportResult(t, uc, id, upperFinal, 0, 1).NextGames
+	next = append(next, reportResult(t, uc, id, lower, 1, 0).NextGames...)
+	lowerFinal := findGame(t, next, entity.GAME_TYPE_LOSERS_BRACKET, 2)
+	checkTeams(t, lowerFinal, upperFinal.Team1ID, lower.Team1ID)
+
+	grandFinal := findGame(t, reportResult(t, uc, id, lowerFinal, 1, 0).NextGames, entity.GAME_TYPE_GRAND_FINAL, 1)
+	if grandFinal.Team1ID != upperFinal.Team2ID || grandFinal.Team2ID != lowerFinal.Team1ID {
+		t.Errorf("grand final %d vs %d, want upper winner %d vs lower winner %d", grandFinal.Team1ID, grandFinal.Team2ID, upperFinal.Team2ID, lowerFinal.Team1ID)
+	}
+}
+
+func findGame(t *testing.T, games []entity.Game, gameType int, round int) entity.Game {
+	t.Helper()
+	for _, game := range games {
+		if game.GameType == gameType && game.Round == round {
+			return game
+		}
+	}
+	t.Fatalf("no %s game of round %d among %+v", entity.GameTypeNames[gameType], round, games)
+	return entity.Game{}
+}
+
+func checkTeams(t *testing.T, game entity.Game, team1 int, team2 int) {
+	t.Helper()
+	if !(game.Team1ID == team1 && game.Team2ID == team2) && !(game.Team1ID == team2 && game.Team2ID == team1) {
+		t.Errorf("%s game of round %d is %d vs %d, want %d vs %d", entity.GameTypeNames[game.GameType], game.Round, game.Team1ID, game.Team2ID, team1, team2)
+	}
+}